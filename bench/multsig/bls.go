@@ -1,7 +1,9 @@
 package multsig
 
 import (
+	"crypto/rand"
 	"fmt"
+	"io"
 	"math/big"
 
 	wts "wts/src"
@@ -43,6 +45,15 @@ type BLS struct {
 }
 
 func GenBLSCRS(n int) BLSCRS {
+	crs, err := GenBLSCRSWithRand(n, rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return crs
+}
+
+// GenBLSCRSWithRand is GenBLSCRS with the generators sampled from rnd
+func GenBLSCRSWithRand(n int, rnd io.Reader) (BLSCRS, error) {
 	domain := fft.NewDomain(uint64(n))
 
 	H := make([]fr.Element, n)
@@ -55,9 +66,11 @@ func GenBLSCRS(n int) BLSCRS {
 
 	gen1, gen2, _, _ := bls.Generators()
 
-	var s1, s2 fr.Element
-	s1.SetRandom()
-	s2.SetRandom()
+	s, err := wts.RandomElements(rnd, 2)
+	if err != nil {
+		return BLSCRS{}, err
+	}
+	s1, s2 := s[0], s[1]
 
 	var (
 		g1      bls.G1Jac
@@ -84,11 +97,20 @@ func GenBLSCRS(n int) BLSCRS {
 		g1InvAf: g1InvAf,
 		domain:  domain,
 		H:       H,
-	}
+	}, nil
 }
 
 // Here t is the degree of the polynomial
 func NewBLS(n, t int, crs BLSCRS) BLS {
+	b, err := NewBLSWithRand(n, t, crs, rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// NewBLSWithRand is NewBLS with the secret shared keys sampled from rnd
+func NewBLSWithRand(n, t int, crs BLSCRS, rnd io.Reader) (BLS, error) {
 	// Assuming n is a power of 2
 	bls := BLS{
 		n:   n,
@@ -96,19 +118,23 @@ func NewBLS(n, t int, crs BLSCRS) BLS {
 		crs: crs,
 	}
 
-	bls.keyGen()
-	return bls
+	if err := bls.keyGen(rnd); err != nil {
+		return BLS{}, err
+	}
+	return bls, nil
 }
 
 // (n,t) secret shared keys
-func (b *BLS) keyGen() {
+func (b *BLS) keyGen(rnd io.Reader) error {
 	sKeys := make([]fr.Element, b.n)
 	pKeys := make([]bls.G1Jac, b.n)
 
 	// Generating t+1 random coefficients
-	for i := 0; i < b.t; i++ {
-		sKeys[i].SetRandom()
+	coeffs, err := wts.RandomElements(rnd, b.t)
+	if err != nil {
+		return err
 	}
+	copy(sKeys, coeffs)
 
 	var pk bls.G1Jac
 	var pkAf bls.G1Affine
@@ -134,6 +160,7 @@ func (b *BLS) keyGen() {
 		pKeys:   pKeysAf,
 		signers: parties,
 	}
+	return nil
 }
 
 // Takes the signing key and signs the message
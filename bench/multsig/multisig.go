@@ -1,7 +1,9 @@
 package multsig
 
 import (
+	"crypto/rand"
 	"fmt"
+	"io"
 	"math/big"
 
 	wts "wts/src"
@@ -41,6 +43,15 @@ type MultSig struct {
 }
 
 func NewMultSig(n int, weights []int) MultSig {
+	m, err := NewMultSigWithRand(n, weights, rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// NewMultSigWithRand is NewMultSig with the signing keys sampled from rnd
+func NewMultSigWithRand(n int, weights []int, rnd io.Reader) (MultSig, error) {
 	g1, g2, g1a, g2a := bls.Generators()
 	m := MultSig{
 		n:       n,
@@ -52,21 +63,25 @@ func NewMultSig(n int, weights []int) MultSig {
 	}
 	m.g1Inv.Neg(&m.g1)
 	m.g1InvAf.FromJacobian(&m.g1Inv)
-	m.keyGen()
+	if err := m.keyGen(rnd); err != nil {
+		return MultSig{}, err
+	}
 
-	return m
+	return m, nil
 }
 
-func (m *MultSig) keyGen() {
+func (m *MultSig) keyGen(rnd io.Reader) error {
 	var vk bls.G1Jac
 	var vkAf bls.G1Affine
-	skeys := make([]fr.Element, m.n)
 	vkeys := make([]bls.G1Jac, m.n)
 	parties := make([]MultSigParty, m.n)
 
 	// Sampling random keys for each signer and computing the corresponding public key
+	skeys, err := wts.RandomElements(rnd, m.n)
+	if err != nil {
+		return err
+	}
 	for i := 0; i < m.n; i++ {
-		skeys[i].SetRandom()
 		vk.ScalarMultiplication(&m.g1, skeys[i].BigInt(&big.Int{}))
 
 		vkeys[i] = vk
@@ -82,6 +97,7 @@ func (m *MultSig) keyGen() {
 		pKeys:   vkeys,
 		parties: parties,
 	}
+	return nil
 }
 
 // Takes the signing key and signs the message
@@ -5,6 +5,8 @@ import (
 	"math/rand"
 	"testing"
 
+	wts "wts/src"
+
 	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/stretchr/testify/assert"
 )
@@ -35,6 +37,32 @@ func TestMultSig(t *testing.T) {
 	assert.Equal(t, m.gverify(roMsg, msig), true)
 }
 
+func TestMultSigSeeded(t *testing.T) {
+	n := 1 << 4
+	weights := make([]int, n)
+
+	m1, err := NewMultSigWithRand(n, weights, wts.NewSeededReader([]byte("multsig")))
+	assert.NoError(t, err)
+	m2, err := NewMultSigWithRand(n, weights, wts.NewSeededReader([]byte("multsig")))
+	assert.NoError(t, err)
+	for i := 0; i < n; i++ {
+		assert.Equal(t, m1.crs.parties[i].pKey.Equal(&m2.crs.parties[i].pKey), true)
+	}
+
+	crs1, err := GenBLSCRSWithRand(n, wts.NewSeededReader([]byte("bls")))
+	assert.NoError(t, err)
+	crs2, err := GenBLSCRSWithRand(n, wts.NewSeededReader([]byte("bls")))
+	assert.NoError(t, err)
+	assert.Equal(t, crs1.g1a.Equal(&crs2.g1a), true)
+	assert.Equal(t, crs1.g2a.Equal(&crs2.g2a), true)
+
+	b1, err := NewBLSWithRand(n, n/2, crs1, wts.NewSeededReader([]byte("bls")))
+	assert.NoError(t, err)
+	b2, err := NewBLSWithRand(n, n/2, crs2, wts.NewSeededReader([]byte("bls")))
+	assert.NoError(t, err)
+	assert.Equal(t, b1.pp.pk.Equal(&b2.pp.pk), true)
+}
+
 func BenchmarkMultSigUW(b *testing.B) {
 	testCases := []struct {
 		name string
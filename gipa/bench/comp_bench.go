@@ -1,9 +1,12 @@
 package wts
 
 import (
+	"io"
 	"math"
 	"math/big"
 
+	"wts/internal/frand"
+
 	"github.com/consensys/gnark-crypto/ecc"
 	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
@@ -25,13 +28,12 @@ func monomials(index int, alpha fr.Element, scalars []fr.Element) {
 	}
 }
 
-func sourceCost(logn int, bases [][]bls.G1Affine) []bls.G1Jac {
-	chals := make([]fr.Element, logn)
-	sLen := int(math.Pow(float64(3), float64(logn)))
-
-	for i := 0; i < logn; i++ {
-		chals[i].SetRandom()
+func sourceCost(logn int, bases [][]bls.G1Affine, rnd io.Reader) ([]bls.G1Jac, error) {
+	chals, err := frand.Elements(rnd, logn)
+	if err != nil {
+		return nil, err
 	}
+	sLen := int(math.Pow(float64(3), float64(logn)))
 
 	resps := make([]bls.G1Jac, logn)
 	scalars := make([]fr.Element, sLen)
@@ -42,10 +44,10 @@ func sourceCost(logn int, bases [][]bls.G1Affine) []bls.G1Jac {
 		count = count * 3
 		resps[i].MultiExp(bases[i], scalars[:count], ecc.MultiExpConfig{})
 	}
-	return resps
+	return resps, nil
 }
 
-func targetCost(logn int, pkeys []bls.G1Affine, ckeys []bls.G2Affine) []bls.GT {
+func targetCost(logn int, pkeys []bls.G1Affine, ckeys []bls.G2Affine, rnd io.Reader) ([]bls.GT, error) {
 	resps := make([]bls.GT, logn)
 
 	var (
@@ -56,7 +58,10 @@ func targetCost(logn int, pkeys []bls.G1Affine, ckeys []bls.G2Affine) []bls.GT {
 	)
 
 	for i := 0; i < logn; i++ {
-		chalFr.SetRandom()
+		var err error
+		if chalFr, err = frand.Element(rnd); err != nil {
+			return nil, err
+		}
 		chalInvFr.Inverse(&chalFr)
 		chalFr.ToBigIntRegular(&chal)
 		chalInvFr.ToBigIntRegular(&chalInv)
@@ -82,5 +87,5 @@ func targetCost(logn int, pkeys []bls.G1Affine, ckeys []bls.G2Affine) []bls.GT {
 
 		resps[i], _ = bls.Pair(pkeys[:mid], ckeys[mid:2*mid])
 	}
-	return resps
+	return resps, nil
 }
@@ -1,6 +1,7 @@
 package wts
 
 import (
+	"crypto/rand"
 	"math/big"
 	"testing"
 
@@ -31,7 +32,9 @@ func BenchmarkTarget(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		targetCost(LOG_N, pkeys, ckeys)
+		if _, err := targetCost(LOG_N, pkeys, ckeys, rand.Reader); err != nil {
+			b.Fatal(err)
+		}
 	}
 }
 
@@ -50,7 +53,9 @@ func BenchmarkSource(b *testing.B) {
 	}
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		sourceCost(LOG_N, bases)
+		if _, err := sourceCost(LOG_N, bases, rand.Reader); err != nil {
+			b.Fatal(err)
+		}
 	}
 }
 
@@ -231,7 +236,9 @@ func TestSource(t *testing.T) {
 		bases[i] = bls.BatchScalarMultiplicationG1(&g1a, skeys)
 	}
 
-	sourceCost(LOG_N, bases)
+	if _, err := sourceCost(LOG_N, bases, rand.Reader); err != nil {
+		t.Fatal(err)
+	}
 }
 
 func TestTarget(t *testing.T) {
@@ -253,5 +260,7 @@ func TestTarget(t *testing.T) {
 		ckeys[i].ScalarMultiplication(&g2a, &skInt)
 	}
 
-	targetCost(LOG_N, pkeys, ckeys)
+	if _, err := targetCost(LOG_N, pkeys, ckeys, rand.Reader); err != nil {
+		t.Fatal(err)
+	}
 }
@@ -1,8 +1,12 @@
 package wts
 
 import (
+	"crypto/rand"
+	"io"
 	"math"
 
+	"wts/internal/frand"
+
 	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	poly "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/polynomial"
@@ -14,6 +18,7 @@ type IPA struct {
 	afgho AFGHO
 	ped   Ped
 	kzg   KZG
+	rnd   io.Reader
 }
 
 type IPAProof struct {
@@ -30,6 +35,11 @@ type Proof struct {
 }
 
 func NewIPA(n int, kzg_crs []bls.G1Affine) IPA {
+	return NewIPAWithRand(n, kzg_crs, rand.Reader)
+}
+
+// NewIPAWithRand is NewIPA with the prover and verifier challenges sampled from rnd
+func NewIPAWithRand(n int, kzg_crs []bls.G1Affine, rnd io.Reader) IPA {
 	ell := int(math.Log2(float64(n)))
 	return IPA{
 		n:     n,
@@ -37,21 +47,24 @@ func NewIPA(n int, kzg_crs []bls.G1Affine) IPA {
 		afgho: NewAFGHO(n),
 		ped:   NewPed(n),
 		kzg:   NewKZG(n, kzg_crs),
+		rnd:   rnd,
 	}
 }
 
 // TODO: To optimize this
-func (p *IPA) prove(gs []bls.G1Affine, fs []fr.Element) IPAProof {
+func (p *IPA) prove(gs []bls.G1Affine, fs []fr.Element) (IPAProof, error) {
 	proofs := make([]Proof, p.ell)
 
 	comm_g := p.afgho.commit_g1(gs)
 	comm_f := p.ped.commit(fs)
-	xs := make([]fr.Element, p.ell)
+	xs, err := frand.Elements(p.rnd, p.ell) // FIXME
+	if err != nil {
+		return IPAProof{}, err
+	}
 
 	for i := 0; i < p.ell; i++ {
 		// FIXME
 		proofs[i] = Proof{}
-		xs[i].SetRandom()
 	}
 
 	kzg_poly := poly.Polynomial(xs)
@@ -62,21 +75,23 @@ func (p *IPA) prove(gs []bls.G1Affine, fs []fr.Element) IPAProof {
 		comm_f: comm_f,
 		kzg_pf: kzg_pf,
 		proof:  proofs,
-	}
+	}, nil
 }
 
 // FIXME: Incomplete implementation
 func (p *IPA) verify_proof(pf IPAProof, comm_g bls.GT, comm_f bls.G1Jac) bool {
 	if comm_g.Equal(&pf.comm_g) && comm_f.Equal(&pf.comm_f) {
-		x_poly := make(poly.Polynomial, p.ell)
-
-		for i := 0; i < p.ell; i++ {
-			// FIXME: Not implemented
-			x_poly[i].SetRandom()
+		// FIXME: Not implemented
+		xs, err := frand.Elements(p.rnd, p.ell)
+		if err != nil {
+			return false
 		}
+		x_poly := poly.Polynomial(xs)
 
-		var x fr.Element
-		x.SetRandom()
+		x, err := frand.Element(p.rnd)
+		if err != nil {
+			return false
+		}
 		y := x_poly.Eval(&x)
 		return p.kzg.verify(pf.kzg_pf.p_tau, pf.kzg_pf.q_tau, x, y)
 	}
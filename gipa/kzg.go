@@ -40,9 +40,8 @@ func (k *KZG) prove(p poly.Polynomial, x fr.Element) (fr.Element, bls.G1Affine)
 
 // We will specifically use this for GIPA, here the prover generates proof at a random evaluation point
 func (k *KZG) comm_prove_rand(p poly.Polynomial) KZGIPA {
-	var x fr.Element
+	// TODO: to open at a point derived from the commitment of the vector
 	comm := k.commit(p)
-	x.SetRandom() // TODO: to fix this, use commitment of the vector
 	qt := make(poly.Polynomial, k.n-1)
 	return KZGIPA{comm, k.eval_exp(qt)}
 }
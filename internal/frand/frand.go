@@ -0,0 +1,32 @@
+// Package frand samples BLS12-381 scalar field elements from an io.Reader
+package frand
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Element samples a field element from rnd.
+// It reads 64 bytes and reduces them modulo r, so the bias is negligible.
+func Element(rnd io.Reader) (fr.Element, error) {
+	var buf [64]byte
+	if _, err := io.ReadFull(rnd, buf[:]); err != nil {
+		return fr.Element{}, err
+	}
+	x := new(big.Int).SetBytes(buf[:])
+	return *new(fr.Element).SetBigInt(x), nil
+}
+
+// Elements fills a fresh slice of n field elements from rnd
+func Elements(rnd io.Reader, n int) ([]fr.Element, error) {
+	els := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		var err error
+		if els[i], err = Element(rnd); err != nil {
+			return nil, err
+		}
+	}
+	return els, nil
+}
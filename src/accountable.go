@@ -0,0 +1,69 @@
+package wts
+
+import (
+	"errors"
+	"fmt"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+var (
+	ErrBitmapLength   = errors.New("wts: signer bitmap does not match the committee size")
+	ErrBitmapMismatch = errors.New("wts: signer bitmap does not match the committed bitvector")
+)
+
+// AccountableSig is a signature that also reveals its signers.
+// The bitmap is checked against the bitvector commitment of the proof, so the signers
+// can be attributed without trusting the aggregator.
+type AccountableSig struct {
+	Sig
+	Bitmap []byte // Bit i%8 of byte i/8 is set when signer i signed
+}
+
+// Signers returns the indices of the signers in increasing order
+func (s *AccountableSig) Signers() []int {
+	var signers []int
+	for i := 0; i < 8*len(s.Bitmap); i++ {
+		if s.Bitmap[i/8]&(1<<(i%8)) != 0 {
+			signers = append(signers, i)
+		}
+	}
+	return signers
+}
+
+// CombineAccountable is combine with the signer bitmap attached to the signature
+func (w *WTS) CombineAccountable(signers []int, sigmas []bls.G2Jac) (AccountableSig, error) {
+	sig, err := w.combine(signers, sigmas)
+	if err != nil {
+		return AccountableSig{}, err
+	}
+	bitmap := make([]byte, (w.n+7)/8)
+	for _, idx := range signers {
+		bitmap[idx/8] |= 1 << (idx % 8)
+	}
+	return AccountableSig{Sig: sig, Bitmap: bitmap}, nil
+}
+
+// VerifyAccountable checks that the bitmap commits to the bitvector of the proof and then
+// verifies the signature as Verify does
+func (w *WTS) VerifyAccountable(msg Message, sigma AccountableSig, ths int) error {
+	if len(sigma.Bitmap) != (w.n+7)/8 {
+		return fmt.Errorf("%w: %d bytes for %d signers", ErrBitmapLength, len(sigma.Bitmap), w.n)
+	}
+	if w.n%8 != 0 && sigma.Bitmap[len(sigma.Bitmap)-1]>>(w.n%8) != 0 {
+		return fmt.Errorf("%w: bits set past signer %d", ErrBitmapLength, w.n-1)
+	}
+
+	var bTau bls.G1Jac
+	weight := 0
+	for _, idx := range sigma.Signers() {
+		bTau.AddMixed(&w.crs.lagHTaus[idx])
+		weight += w.weights[idx]
+	}
+	bTauAff := *new(bls.G1Affine).FromJacobian(&bTau)
+	if !bTauAff.Equal(&sigma.bTau) || weight != sigma.ths {
+		return ErrBitmapMismatch
+	}
+
+	return w.Verify(msg, sigma.Sig, ths)
+}
@@ -0,0 +1,46 @@
+package wts
+
+import (
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountable(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 4
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+
+	w := NewWTS(n, weights, GenCRS(n))
+	w.preProcess()
+
+	signers := []int{9, 1, 4, 15}
+	sigmas := make([]bls.G2Jac, len(signers))
+	ths := 0
+	for i, idx := range signers {
+		sigmas[i] = w.psign(msg, w.signers[idx])
+		ths += weights[idx]
+	}
+
+	sig, err := w.CombineAccountable(signers, sigmas)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 4, 9, 15}, sig.Signers())
+	assert.Equal(t, n/8, len(sig.Bitmap))
+	assert.NoError(t, w.VerifyAccountable(msg, sig, ths))
+
+	// Claiming a different signer set is caught
+	bad := sig
+	bad.Bitmap = append([]byte{}, sig.Bitmap...)
+	bad.Bitmap[0] ^= 1
+	assert.ErrorIs(t, w.VerifyAccountable(msg, bad, ths), ErrBitmapMismatch)
+
+	bad.Bitmap = sig.Bitmap[:1]
+	assert.ErrorIs(t, w.VerifyAccountable(msg, bad, ths), ErrBitmapLength)
+
+	// The underlying proof is still checked
+	assert.ErrorIs(t, w.VerifyAccountable(msg, sig, ths+1), ErrInsufficientWeight)
+}
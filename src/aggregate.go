@@ -0,0 +1,249 @@
+package wts
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+var (
+	ErrAggregateOverlap   = errors.New("wts: aggregates share a signer")
+	ErrAggregateCommittee = errors.New("wts: aggregate belongs to another committee")
+	ErrAggregateEncoding  = errors.New("wts: malformed aggregate encoding")
+)
+
+// Aggregate is an intermediate aggregate of partial signatures, for aggregation trees.
+// Everything in it is linear in the bitvector, so aggregates of disjoint signer sets are
+// merged by adding them up. Only the binary proof and the Fiat-Shamir challenge are left
+// to CombineAggregate at the root.
+type Aggregate struct {
+	cid     CommitteeID
+	signers []int // Sorted
+	weight  int
+
+	bTau, aggPk, aggPkB, qTau, pTau, rTau bls.G1Jac
+	qwTau, rwTau, pwTauH                  bls.G1Jac
+	b2Tau, aggSig                         bls.G2Jac
+}
+
+// Signers returns the signers of the aggregate in increasing order
+func (a *Aggregate) Signers() []int {
+	return append([]int(nil), a.signers...)
+}
+
+// Weight returns the weight of the signers of the aggregate
+func (a *Aggregate) Weight() int {
+	return a.weight
+}
+
+// NewAggregate aggregates partial signatures, normalizing the signers as combine does.
+// The partial signatures are assumed to be valid, see pverify.
+func (w *WTS) NewAggregate(signers []int, sigmas []bls.G2Jac) (Aggregate, error) {
+	signers, sigmas, err := w.normalizeSigners(signers, sigmas)
+	if err != nil {
+		return Aggregate{}, err
+	}
+	agg := w.aggregate(signers, sigmas)
+	agg.cid = w.CommitteeID()
+	return agg, nil
+}
+
+// Merge returns the aggregate of the signers of a and b, which must be disjoint
+func (a Aggregate) Merge(b Aggregate) (Aggregate, error) {
+	if a.cid != b.cid {
+		return Aggregate{}, ErrAggregateCommittee
+	}
+	signers := make([]int, 0, len(a.signers)+len(b.signers))
+	i, j := 0, 0
+	for i < len(a.signers) || j < len(b.signers) {
+		switch {
+		case j == len(b.signers) || (i < len(a.signers) && a.signers[i] < b.signers[j]):
+			signers = append(signers, a.signers[i])
+			i++
+		case i == len(a.signers) || b.signers[j] < a.signers[i]:
+			signers = append(signers, b.signers[j])
+			j++
+		default:
+			return Aggregate{}, fmt.Errorf("%w: %d", ErrAggregateOverlap, a.signers[i])
+		}
+	}
+
+	a.signers = signers
+	a.weight += b.weight
+	for _, p := range []struct{ dst, src *bls.G1Jac }{
+		{&a.bTau, &b.bTau}, {&a.aggPk, &b.aggPk}, {&a.aggPkB, &b.aggPkB},
+		{&a.qTau, &b.qTau}, {&a.pTau, &b.pTau}, {&a.rTau, &b.rTau},
+		{&a.qwTau, &b.qwTau}, {&a.rwTau, &b.rwTau}, {&a.pwTauH, &b.pwTauH},
+	} {
+		p.dst.AddAssign(p.src)
+	}
+	a.b2Tau.AddAssign(&b.b2Tau)
+	a.aggSig.AddAssign(&b.aggSig)
+	return a, nil
+}
+
+// CombineAggregate completes the signature of an aggregate: it computes the binary proof
+// for the signer set and the Fiat-Shamir challenge. The result is the signature combine
+// returns for the same signers.
+func (w *WTS) CombineAggregate(a Aggregate) (Sig, error) {
+	if len(a.signers) == 0 {
+		return Sig{}, ErrNoSigners
+	}
+	if a.cid != w.CommitteeID() {
+		return Sig{}, ErrAggregateCommittee
+	}
+	parts := a.parts(w.crs.g2, w.binaryPf(a.signers))
+	xi := w.getFSChal(a.cid, []bls.G1Affine{w.pp.pComm, w.pp.wTau, parts.bTau, parts.aggPk}, parts.weight)
+	return parts.sig(xi), nil
+}
+
+// Computes the parts of the signature that are linear in the bitvector,
+// signers must be normalized
+func (w *WTS) aggregate(signers []int, sigmas []bls.G2Jac) Aggregate {
+	var agg Aggregate
+	agg.signers = signers
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for _, idx := range signers {
+			agg.bTau.AddMixed(&w.crs.lagHTaus[idx])
+			agg.b2Tau.AddMixed(&w.crs.lag2HTaus[idx])
+			agg.qTau.AddMixed(&w.pp.qTaus[idx])
+			agg.aggPk.AddMixed(&w.pp.pKeys[idx])
+			agg.aggPkB.AddMixed(&w.pp.pKeysB[idx])
+			agg.pTau.AddAssign(&w.pp.hTausH[idx])
+			agg.weight += w.weights[idx]
+		}
+
+		// Aggregating the signature
+		for _, sig := range sigmas {
+			agg.aggSig.AddAssign(&sig)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		agg.qwTau, agg.rwTau, agg.pwTauH = w.weightsPf(signers, w.weights)
+	}()
+
+	go func() {
+		defer wg.Done()
+		agg.rTau = w.secretPf(signers)
+	}()
+	wg.Wait()
+
+	return agg
+}
+
+// The combineParts of the aggregate, with qB the binary proof for its signers
+func (a *Aggregate) parts(g2 bls.G2Jac, qB bls.G1Affine) combineParts {
+	bNegTau := g2
+	bNegTau.SubAssign(&a.b2Tau)
+	return combineParts{
+		bTau:    *new(bls.G1Affine).FromJacobian(&a.bTau),
+		aggPk:   *new(bls.G1Affine).FromJacobian(&a.aggPk),
+		qB:      qB,
+		qTau:    a.qTau,
+		pTau:    a.pTau,
+		rTau:    a.rTau,
+		aggPkB:  a.aggPkB,
+		qwTau:   a.qwTau,
+		rwTau:   a.rwTau,
+		pwTauH:  a.pwTauH,
+		bNegTau: bNegTau,
+		aggSig:  a.aggSig,
+		weight:  a.weight,
+		signers: a.signers,
+	}
+}
+
+// An encoded Aggregate is a header, 4 bytes per signer and the points
+const (
+	aggregateHeaderSize = 32 + 8 + 4
+	aggregatePointsSize = 9*bls.SizeOfG1AffineCompressed + 2*bls.SizeOfG2AffineCompressed
+)
+
+// MarshalBinary encodes the committee, the weight, the number of signers and the signers,
+// followed by the group elements in compressed form
+func (a *Aggregate) MarshalBinary() ([]byte, error) {
+	if a.weight < 0 {
+		return nil, fmt.Errorf("%w: weight %d", ErrAggregateEncoding, a.weight)
+	}
+	if uint64(len(a.signers)) > uint64(^uint32(0)) {
+		return nil, fmt.Errorf("%w: %d signers", ErrAggregateEncoding, len(a.signers))
+	}
+	buf := make([]byte, 0, aggregateHeaderSize+4*len(a.signers)+aggregatePointsSize)
+	buf = append(buf, a.cid[:]...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(a.weight))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(a.signers)))
+	for _, idx := range a.signers {
+		if idx < 0 || uint64(idx) > uint64(^uint32(0)) {
+			return nil, fmt.Errorf("%w: signer %d", ErrAggregateEncoding, idx)
+		}
+		buf = binary.BigEndian.AppendUint32(buf, uint32(idx))
+	}
+	g1s := []*bls.G1Jac{&a.bTau, &a.aggPk, &a.aggPkB, &a.qTau, &a.pTau, &a.rTau, &a.qwTau, &a.rwTau, &a.pwTauH}
+	for _, p := range g1s {
+		b := new(bls.G1Affine).FromJacobian(p).Bytes()
+		buf = append(buf, b[:]...)
+	}
+	for _, p := range []*bls.G2Jac{&a.b2Tau, &a.aggSig} {
+		b := new(bls.G2Affine).FromJacobian(p).Bytes()
+		buf = append(buf, b[:]...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes an encoding of MarshalBinary, the signers are checked to be
+// increasing and the points to be in their groups
+func (a *Aggregate) UnmarshalBinary(data []byte) error {
+	const g1Size, g2Size = bls.SizeOfG1AffineCompressed, bls.SizeOfG2AffineCompressed
+	if len(data) < aggregateHeaderSize {
+		return fmt.Errorf("%w: %d bytes", ErrAggregateEncoding, len(data))
+	}
+	var dec Aggregate
+	copy(dec.cid[:], data[:32])
+	weight := binary.BigEndian.Uint64(data[32:])
+	if weight > uint64(^uint(0)>>1) {
+		return fmt.Errorf("%w: weight %d", ErrAggregateEncoding, weight)
+	}
+	dec.weight = int(weight)
+	count := uint64(binary.BigEndian.Uint32(data[40:]))
+	if uint64(len(data)) != aggregateHeaderSize+4*count+aggregatePointsSize {
+		return fmt.Errorf("%w: %d bytes for %d signers", ErrAggregateEncoding, len(data), count)
+	}
+
+	off := aggregateHeaderSize
+	dec.signers = make([]int, count)
+	for i := range dec.signers {
+		dec.signers[i] = int(binary.BigEndian.Uint32(data[off:]))
+		if i > 0 && dec.signers[i] <= dec.signers[i-1] {
+			return fmt.Errorf("%w: signers are not increasing", ErrAggregateEncoding)
+		}
+		off += 4
+	}
+	g1s := []*bls.G1Jac{&dec.bTau, &dec.aggPk, &dec.aggPkB, &dec.qTau, &dec.pTau, &dec.rTau, &dec.qwTau, &dec.rwTau, &dec.pwTauH}
+	for _, p := range g1s {
+		var q bls.G1Affine
+		if _, err := q.SetBytes(data[off : off+g1Size]); err != nil {
+			return fmt.Errorf("%w: %v", ErrAggregateEncoding, err)
+		}
+		p.FromAffine(&q)
+		off += g1Size
+	}
+	for _, p := range []*bls.G2Jac{&dec.b2Tau, &dec.aggSig} {
+		var q bls.G2Affine
+		if _, err := q.SetBytes(data[off : off+g2Size]); err != nil {
+			return fmt.Errorf("%w: %v", ErrAggregateEncoding, err)
+		}
+		p.FromAffine(&q)
+		off += g2Size
+	}
+	*a = dec
+	return nil
+}
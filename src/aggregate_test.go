@@ -0,0 +1,139 @@
+package wts
+
+import (
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateMerge(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 4
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+	w := NewWTS(n, weights, GenCRS(n))
+	w.preProcess()
+
+	sets := [][]int{{0, 5, 9}, {1, 2, 12}, {7, 15}}
+	var aggs []Aggregate
+	var signers []int
+	var sigmas []bls.G2Jac
+	ths := 0
+	for _, set := range sets {
+		var setSigmas []bls.G2Jac
+		for _, idx := range set {
+			setSigmas = append(setSigmas, w.psign(msg, w.signers[idx]))
+			ths += weights[idx]
+		}
+		agg, err := w.NewAggregate(set, setSigmas)
+		assert.NoError(t, err)
+		aggs = append(aggs, agg)
+		signers = append(signers, set...)
+		sigmas = append(sigmas, setSigmas...)
+	}
+
+	// (a+b)+c and a+(b+c) give the signature combine computes from scratch
+	ab, err := aggs[0].Merge(aggs[1])
+	assert.NoError(t, err)
+	left, err := ab.Merge(aggs[2])
+	assert.NoError(t, err)
+	bc, err := aggs[1].Merge(aggs[2])
+	assert.NoError(t, err)
+	right, err := aggs[0].Merge(bc)
+	assert.NoError(t, err)
+	assert.Equal(t, left.Signers(), []int{0, 1, 2, 5, 7, 9, 12, 15})
+	assert.Equal(t, left.Weight(), ths)
+
+	want, err := w.combine(signers, sigmas)
+	assert.NoError(t, err)
+	for _, agg := range []Aggregate{left, right} {
+		sig, err := w.CombineAggregate(agg)
+		assert.NoError(t, err)
+		assert.NoError(t, w.Verify(msg, sig, ths))
+		assert.Equal(t, sig.bTau, want.bTau)
+		assert.Equal(t, sig.pi, want.pi)
+		assert.Equal(t, sig.pTau, want.pTau)
+		assert.Equal(t, sig.xi, want.xi)
+	}
+
+	_, err = left.Merge(aggs[1])
+	assert.ErrorIs(t, err, ErrAggregateOverlap)
+	_, err = w.CombineAggregate(Aggregate{})
+	assert.ErrorIs(t, err, ErrNoSigners)
+
+	other := NewWTS(n, weights, GenCRS(n))
+	other.preProcess()
+	otherAgg, err := other.NewAggregate([]int{3}, []bls.G2Jac{other.psign(msg, other.signers[3])})
+	assert.NoError(t, err)
+	_, err = left.Merge(otherAgg)
+	assert.ErrorIs(t, err, ErrAggregateCommittee)
+	_, err = w.CombineAggregate(otherAgg)
+	assert.ErrorIs(t, err, ErrAggregateCommittee)
+}
+
+func TestAggregateEncoding(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 3
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+	w := NewWTS(n, weights, GenCRS(n))
+	w.preProcess()
+
+	// Aggregates travel encoded between the levels of the tree
+	var dec []Aggregate
+	var signers []int
+	var sigmas []bls.G2Jac
+	for _, set := range [][]int{{2, 6}, {0, 3, 7}} {
+		var setSigmas []bls.G2Jac
+		for _, idx := range set {
+			setSigmas = append(setSigmas, w.psign(msg, w.signers[idx]))
+		}
+		agg, err := w.NewAggregate(set, setSigmas)
+		assert.NoError(t, err)
+		enc, err := agg.MarshalBinary()
+		assert.NoError(t, err)
+		var a Aggregate
+		assert.NoError(t, a.UnmarshalBinary(enc))
+		dec = append(dec, a)
+		signers = append(signers, set...)
+		sigmas = append(sigmas, setSigmas...)
+	}
+	merged, err := dec[0].Merge(dec[1])
+	assert.NoError(t, err)
+	enc, err := merged.MarshalBinary()
+	assert.NoError(t, err)
+	var root Aggregate
+	assert.NoError(t, root.UnmarshalBinary(enc))
+	again, err := root.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, again, enc)
+
+	sig, err := w.CombineAggregate(root)
+	assert.NoError(t, err)
+	want, err := w.combine(signers, sigmas)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Verify(msg, sig, 1+3+4+7+8))
+	assert.Equal(t, sig.pi, want.pi)
+	assert.Equal(t, sig.pTau, want.pTau)
+
+	var a Aggregate
+	assert.ErrorIs(t, a.UnmarshalBinary(enc[:len(enc)-1]), ErrAggregateEncoding)
+	assert.ErrorIs(t, a.UnmarshalBinary(enc[:aggregateHeaderSize-1]), ErrAggregateEncoding)
+
+	// Signers out of order
+	bad := append([]byte(nil), enc...)
+	copy(bad[aggregateHeaderSize:], bad[aggregateHeaderSize+4:aggregateHeaderSize+8])
+	assert.ErrorIs(t, a.UnmarshalBinary(bad), ErrAggregateEncoding)
+
+	// A point outside of G1
+	bad = append([]byte(nil), enc...)
+	p := nonSubgroupG1()
+	b := p.Bytes()
+	copy(bad[aggregateHeaderSize+4*len(root.signers):], b[:])
+	assert.ErrorIs(t, a.UnmarshalBinary(bad), ErrAggregateEncoding)
+}
@@ -0,0 +1,138 @@
+package wts
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+var ErrBatchLength = errors.New("wts: batch is empty or its lengths differ")
+
+const batchTag = "WTS-BATCH-V1"
+
+// BatchSig is a signature on several messages by the same signers.
+// The proof only depends on the signer set, so it is shared and only the aggregated
+// BLS signature differs per message.
+type BatchSig struct {
+	Sig     // aggSig is left to zero
+	aggSigs []bls.G2Jac
+}
+
+// Len is the number of messages of the batch
+func (s *BatchSig) Len() int {
+	return len(s.aggSigs)
+}
+
+// At returns the plain signature on message j of the batch, which Verify accepts
+func (s *BatchSig) At(j int) Sig {
+	sig := s.Sig
+	sig.aggSig = s.aggSigs[j]
+	return sig
+}
+
+// CombineBatch is combine for a batch of messages signed by the same signers,
+// where sigmas[j][i] is the partial signature of signers[i] on message j.
+// The signer set proofs are computed once for the whole batch.
+func (w *WTS) CombineBatch(signers []int, sigmas [][]bls.G2Jac) (BatchSig, error) {
+	if len(sigmas) == 0 {
+		return BatchSig{}, fmt.Errorf("%w: no messages", ErrBatchLength)
+	}
+	parts, err := w.combineParts(signers, sigmas[0])
+	if err != nil {
+		return BatchSig{}, err
+	}
+
+	aggSigs := make([]bls.G2Jac, len(sigmas))
+	aggSigs[0] = parts.aggSig
+	for j := 1; j < len(sigmas); j++ {
+		_, nSigmas, err := w.normalizeSigners(signers, sigmas[j])
+		if err != nil {
+			return BatchSig{}, fmt.Errorf("message %d: %w", j, err)
+		}
+		for _, sig := range nSigmas {
+			aggSigs[j].AddAssign(&sig)
+		}
+	}
+
+	xi := w.getFSChal(w.CommitteeID(), []bls.G1Affine{w.pp.pComm, w.pp.wTau, parts.bTau, parts.aggPk}, parts.weight)
+	sig := parts.sig(xi)
+	sig.aggSig = bls.G2Jac{}
+	return BatchSig{Sig: sig, aggSigs: aggSigs}, nil
+}
+
+// VerifyBatch checks that sigma is a signature of weight at least ths on every message of msgs.
+// The BLS signatures are checked together with a linear combination whose coefficients are
+// derived by hashing the batch, and the shared proof once, so its cost barely grows with the
+// number of messages.
+func (w *WTS) VerifyBatch(msgs []Message, sigma BatchSig, ths int) error {
+	if len(msgs) == 0 || len(msgs) != len(sigma.aggSigs) {
+		return fmt.Errorf("%w: %d messages, %d signatures", ErrBatchLength, len(msgs), len(sigma.aggSigs))
+	}
+	fail := func(err error) error {
+		return &VerifyError{Err: err, Claimed: sigma.ths, Required: ths}
+	}
+
+	// 0. Checking the claimed weight
+	if sigma.ths < ths {
+		return fail(ErrInsufficientWeight)
+	}
+
+	// 1. Checking e(aggPk, sum_j r_j.H(m_j)) = e(g, sum_j r_j.aggSig_j)
+	cid := w.CommitteeID()
+	roMsgs := make([]bls.G2Affine, len(msgs))
+	for j, msg := range msgs {
+		var err error
+		if roMsgs[j], err = hashMsg(cid, msg); err != nil {
+			return err
+		}
+	}
+	aggSigs := make([]bls.G2Affine, len(msgs))
+	for j := range aggSigs {
+		aggSigs[j].FromJacobian(&sigma.aggSigs[j])
+	}
+	rs := batchRandomizers(cid, sigma.aggPk, msgs, aggSigs)
+	var roMsg, aggSig bls.G2Affine
+	roMsg.MultiExp(roMsgs, rs, ecc.MultiExpConfig{})
+	aggSig.MultiExp(aggSigs, rs, ecc.MultiExpConfig{})
+	if res, _ := bls.PairingCheck([]bls.G1Affine{sigma.aggPk, w.crs.g1InvAff}, []bls.G2Affine{roMsg, aggSig}); !res {
+		return fail(ErrAggSig)
+	}
+
+	xi := w.getFSChal(cid, []bls.G1Affine{w.pp.pComm, w.pp.wTau, sigma.bTau, sigma.aggPk}, sigma.ths)
+	var wt bls.G1Affine
+	wt.ScalarMultiplication(&w.crs.g1a, big.NewInt(int64(sigma.ths)))
+	return w.verifyProof(sigma.Sig, xi, []bls.G1Affine{wt}, fail)
+}
+
+// Derives the coefficients of the random linear combination in VerifyBatch by hashing the
+// whole batch, so they are fixed only once the messages and signatures are, without
+// relying on the randomness source of the verifier.
+func batchRandomizers(cid CommitteeID, aggPk bls.G1Affine, msgs []Message, aggSigs []bls.G2Affine) []fr.Element {
+	hFunc := sha256.New()
+	hFunc.Write([]byte(batchTag))
+	hFunc.Write(cid[:])
+	pk := aggPk.Bytes()
+	hFunc.Write(pk[:])
+	for j, msg := range msgs {
+		hFunc.Write(binary.BigEndian.AppendUint64(nil, uint64(len(msg))))
+		hFunc.Write(msg)
+		sig := aggSigs[j].Bytes()
+		hFunc.Write(sig[:])
+	}
+	seed := hFunc.Sum(nil)
+
+	rs := make([]fr.Element, len(msgs))
+	for j := range rs {
+		hFunc.Reset()
+		hFunc.Write(seed)
+		hFunc.Write(binary.BigEndian.AppendUint64(nil, uint64(j)))
+		rs[j].SetBytes(hFunc.Sum(nil))
+	}
+	return rs
+}
@@ -0,0 +1,62 @@
+package wts
+
+import (
+	"fmt"
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCombineBatch(t *testing.T) {
+	n := 1 << 4
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+	w := NewWTS(n, weights, GenCRS(n))
+	w.preProcess()
+
+	signers := []int{9, 1, 4, 12, 2}
+	ths := 0
+	for _, idx := range signers {
+		ths += weights[idx]
+	}
+	msgs := make([]Message, 4)
+	sigmas := make([][]bls.G2Jac, len(msgs))
+	for j := range msgs {
+		msgs[j] = []byte(fmt.Sprintf("message %d", j))
+		for _, idx := range signers {
+			sigmas[j] = append(sigmas[j], w.psign(msgs[j], w.signers[idx]))
+		}
+	}
+
+	sig, err := w.CombineBatch(signers, sigmas)
+	assert.NoError(t, err)
+	assert.Equal(t, sig.Len(), len(msgs))
+	assert.NoError(t, w.VerifyBatch(msgs, sig, ths))
+	for j, msg := range msgs {
+		assert.NoError(t, w.Verify(msg, sig.At(j), ths))
+	}
+
+	assert.ErrorIs(t, w.VerifyBatch(msgs, sig, ths+1), ErrInsufficientWeight)
+	assert.ErrorIs(t, w.VerifyBatch(msgs[:3], sig, ths), ErrBatchLength)
+	swapped := append([]Message{msgs[1], msgs[0]}, msgs[2:]...)
+	assert.ErrorIs(t, w.VerifyBatch(swapped, sig, ths), ErrAggSig)
+
+	// Errors that cancel out in the plain sum of the signatures
+	bad := sig
+	bad.aggSigs = append([]bls.G2Jac(nil), sig.aggSigs...)
+	bad.aggSigs[0].AddAssign(&w.crs.g2)
+	bad.aggSigs[1].SubAssign(&w.crs.g2)
+	assert.ErrorIs(t, w.VerifyBatch(msgs, bad, ths), ErrAggSig)
+
+	bad = sig
+	bad.pTau = w.crs.g1a
+	assert.ErrorIs(t, w.VerifyBatch(msgs, bad, ths), ErrRTauDegree)
+
+	_, err = w.CombineBatch(signers, nil)
+	assert.ErrorIs(t, err, ErrBatchLength)
+	_, err = w.CombineBatch(signers, [][]bls.G2Jac{sigmas[0], sigmas[1][1:]})
+	assert.ErrorIs(t, err, ErrSignerMismatch)
+}
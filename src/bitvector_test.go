@@ -0,0 +1,63 @@
+package wts
+
+import (
+	"math/big"
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+// A single signer claiming three times its weight with a bitvector that is 3 in G2 and 0 in G1
+func TestMismatchedBitvector(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 4
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+	w := NewWTS(n, weights, GenCRS(n))
+	w.preProcess()
+
+	// Every part of the proof is linear in the bitvector
+	j := 5
+	three := big.NewInt(3)
+	triple := func(p bls.G1Jac) bls.G1Affine {
+		p.ScalarMultiplication(&p, three)
+		return *new(bls.G1Affine).FromJacobian(&p)
+	}
+	var qTau, aggPk, aggPkB, pTau bls.G1Jac
+	qTau.FromAffine(&w.pp.qTaus[j])
+	aggPk.FromAffine(&w.pp.pKeys[j])
+	aggPkB.FromAffine(&w.pp.pKeysB[j])
+	pTau = w.pp.hTausH[j]
+	rTau := w.secretPf([]int{j})
+	qwTau, rwTau, pwTauH := w.weightsPf([]int{j}, w.weights)
+
+	var b2Tau, bNegTau, aggSig bls.G2Jac
+	b2Tau.FromAffine(&w.crs.lag2HTaus[j])
+	b2Tau.ScalarMultiplication(&b2Tau, three)
+	bNegTau = w.crs.g2
+	bNegTau.SubAssign(&b2Tau)
+	aggSig = w.psign(msg, w.signers[j])
+	aggSig.ScalarMultiplication(&aggSig, three)
+
+	sig := Sig{
+		bTau:    bls.G1Affine{},
+		qB:      bls.G1Affine{},
+		bNegTau: *new(bls.G2Affine).FromJacobian(&bNegTau),
+		aggPk:   triple(aggPk),
+		aggPkB:  triple(aggPkB),
+		aggSig:  aggSig,
+		ths:     3 * weights[j],
+	}
+	xi := w.getFSChal(w.CommitteeID(), []bls.G1Affine{w.pp.pComm, w.pp.wTau, sig.bTau, sig.aggPk}, sig.ths)
+	xiInt := xi.BigInt(&big.Int{})
+	qTau.AddAssign(qwTau.ScalarMultiplication(&qwTau, xiInt))
+	rTau.AddAssign(rwTau.ScalarMultiplication(&rwTau, xiInt))
+	pTau.AddAssign(pwTauH.ScalarMultiplication(&pwTauH, xiInt))
+	sig.pi = IPAProof{qTau: triple(qTau), rTau: triple(rTau)}
+	sig.pTau = triple(pTau)
+
+	assert.ErrorIs(t, w.Verify(msg, sig, sig.ths), ErrBitvector)
+}
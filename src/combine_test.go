@@ -0,0 +1,50 @@
+package wts
+
+import (
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCombineValidation(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 4
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+
+	w := NewWTS(n, weights, GenCRS(n))
+	w.preProcess()
+	sigmas := make([]bls.G2Jac, n)
+	for i := 0; i < n; i++ {
+		sigmas[i] = w.psign(msg, w.signers[i])
+	}
+
+	_, err := w.combine(nil, nil)
+	assert.ErrorIs(t, err, ErrNoSigners)
+	_, err = w.combine([]int{1, 2}, sigmas[1:2])
+	assert.ErrorIs(t, err, ErrSignerMismatch)
+	_, err = w.combine([]int{1, n}, []bls.G2Jac{sigmas[1], sigmas[1]})
+	assert.ErrorIs(t, err, ErrSignerRange)
+	_, err = w.combine([]int{-1}, sigmas[:1])
+	assert.ErrorIs(t, err, ErrSignerRange)
+	_, err = w.combine([]int{3, 3}, []bls.G2Jac{sigmas[3], sigmas[4]})
+	assert.ErrorIs(t, err, ErrDuplicateSigner)
+
+	// Unsorted signers with repetitions are normalized, and weight is counted once
+	signers := []int{5, 2, 9, 2, 5, 0}
+	sigs := []bls.G2Jac{sigmas[5], sigmas[2], sigmas[9], sigmas[2], sigmas[5], sigmas[0]}
+	sig, err := w.combine(signers, sigs)
+	assert.NoError(t, err)
+	ths := weights[0] + weights[2] + weights[5] + weights[9]
+	assert.Equal(t, ths, sig.ths)
+	assert.NoError(t, w.Verify(msg, sig, ths))
+	assert.Equal(t, []int{5, 2, 9, 2, 5, 0}, signers)
+
+	sorted, err := w.combine([]int{0, 2, 5, 9}, []bls.G2Jac{sigmas[0], sigmas[2], sigmas[5], sigmas[9]})
+	assert.NoError(t, err)
+	assert.Equal(t, sorted.bTau.Equal(&sig.bTau), true)
+	assert.Equal(t, sorted.pi.qTau.Equal(&sig.pi.qTau), true)
+}
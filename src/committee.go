@@ -0,0 +1,86 @@
+package wts
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+const (
+	crsIDTag       = "WTS-CRS-V1"
+	committeeIDTag = "WTS-COMMITTEE-V1"
+	msgDSTPrefix   = "WTS-BLS12381G2_XMD:SHA-256_SSWU_RO_"
+)
+
+// CommitteeID identifies a committee: its CRS, signing keys, weights, size and epoch.
+// It is bound into the message hashing and into the proof transcript, so a signature
+// produced by one committee or in one epoch does not verify for another.
+type CommitteeID [32]byte
+
+func (id CommitteeID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// ID is a digest of the trapdoor dependent elements of the CRS
+func (crs *CRS) ID() [32]byte {
+	return crsID(len(crs.H), []bls.G1Affine{crs.g1Ba, crs.h1a}, []bls.G2Affine{crs.g2Ba, crs.h2a, crs.hTauHAff, crs.g2Tau, crs.vHTau})
+}
+
+func crsID(n int, g1s []bls.G1Affine, g2s []bls.G2Affine) [32]byte {
+	hFunc := sha256.New()
+	hFunc.Write([]byte(crsIDTag))
+	for _, p := range g1s {
+		b := p.Bytes()
+		hFunc.Write(b[:])
+	}
+	for _, p := range g2s {
+		b := p.Bytes()
+		hFunc.Write(b[:])
+	}
+	hFunc.Write(binary.BigEndian.AppendUint64(nil, uint64(n)))
+	var id [32]byte
+	copy(id[:], hFunc.Sum(nil))
+	return id
+}
+
+// SetEpoch changes the epoch the committee signs in
+func (w *WTS) SetEpoch(epoch uint64) {
+	w.epoch = epoch
+	w.updateCommitteeID()
+}
+
+// CommitteeID returns the digest of the CRS, pComm, the weight commitments, n, the epoch
+// and the registry, if any.
+func (w *WTS) CommitteeID() CommitteeID {
+	return w.cid
+}
+
+// Recomputes the cached CommitteeID, called whenever one of its inputs changes
+func (w *WTS) updateCommitteeID() {
+	w.cid = committeeID(w.crsID, w.pp.pComm, w.pp.wTau, w.pp.wTaus, w.n, w.epoch, w.registryDigest)
+}
+
+func committeeID(crsID [32]byte, pComm, wTau bls.G1Affine, wTaus []bls.G1Affine, n int, epoch uint64, registry *[32]byte) CommitteeID {
+	hFunc := sha256.New()
+	hFunc.Write([]byte(committeeIDTag))
+	hFunc.Write(crsID[:])
+	for _, p := range append([]bls.G1Affine{pComm, wTau}, wTaus...) {
+		b := p.Bytes()
+		hFunc.Write(b[:])
+	}
+	hFunc.Write(binary.BigEndian.AppendUint64(nil, uint64(n)))
+	hFunc.Write(binary.BigEndian.AppendUint64(nil, epoch))
+	if registry != nil {
+		hFunc.Write(registry[:])
+	}
+	var id CommitteeID
+	copy(id[:], hFunc.Sum(nil))
+	return id
+}
+
+// Hashes the message to G2 with a domain separation tag bound to the committee
+func hashMsg(cid CommitteeID, msg Message) (bls.G2Affine, error) {
+	return bls.HashToG2(msg, []byte(msgDSTPrefix+cid.String()))
+}
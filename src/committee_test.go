@@ -0,0 +1,111 @@
+package wts
+
+import (
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitteeID(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 4
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+
+	crs := GenCRS(n)
+	w1 := NewWTS(n, weights, crs)
+	w1.preProcess()
+	w2 := NewWTS(n, weights, crs)
+	w2.preProcess()
+	assert.NotEqual(t, w1.CommitteeID(), w2.CommitteeID())
+
+	sign := func(w *WTS) (Sig, int) {
+		signers := GetRangeTo(n)
+		sigmas := make([]bls.G2Jac, n)
+		ths := 0
+		for i := 0; i < n; i++ {
+			sigmas[i] = w.psign(msg, w.signers[i])
+			ths += weights[i]
+		}
+		sig, err := w.combine(signers, sigmas)
+		assert.NoError(t, err)
+		return sig, ths
+	}
+
+	// Signatures do not verify for another committee
+	sig, ths := sign(&w1)
+	assert.Equal(t, w1.gverify(msg, sig, ths), true)
+	assert.Equal(t, w2.gverify(msg, sig, ths), false)
+
+	// nor in another epoch of the same committee
+	id := w1.CommitteeID()
+	w1.SetEpoch(1)
+	assert.NotEqual(t, id, w1.CommitteeID())
+	assert.Equal(t, w1.gverify(msg, sig, ths), false)
+
+	sig, ths = sign(&w1)
+	assert.Equal(t, w1.gverify(msg, sig, ths), true)
+	w1.SetEpoch(0)
+	assert.Equal(t, w1.gverify(msg, sig, ths), false)
+}
+
+// Partial signatures made right after keyGen verify once the committee is preprocessed
+func TestCommitteeIDBeforePreProcess(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 4
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+
+	w := NewWTS(n, weights, GenCRS(n))
+	id := w.CommitteeID()
+	signers := GetRangeTo(n)
+	sigmas := make([]bls.G2Jac, n)
+	ths := 0
+	for i := 0; i < n; i++ {
+		sigmas[i] = w.psign(msg, w.signers[i])
+		ths += weights[i]
+	}
+
+	w.preProcess()
+	assert.Equal(t, id, w.CommitteeID())
+	sig, err := w.combine(signers, sigmas)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Verify(msg, sig, ths))
+}
+
+// The cached CommitteeID follows every change of its inputs
+func TestCommitteeIDCache(t *testing.T) {
+	n := 1 << 3
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+	w := NewWTS(n, weights, GenCRS(n))
+	fresh := func() CommitteeID {
+		return committeeID(w.crs.ID(), w.pp.pComm, w.pp.wTau, w.pp.wTaus, w.n, w.epoch, w.registryDigest)
+	}
+	assert.Equal(t, w.CommitteeID(), fresh())
+
+	w.SetEpoch(7)
+	assert.Equal(t, w.CommitteeID(), fresh())
+
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = KeyID(w.pp.pKeys[i])
+	}
+	r, err := NewRegistry(ids, n)
+	assert.NoError(t, err)
+	id := w.CommitteeID()
+	assert.NoError(t, w.SetRegistry(r))
+	assert.NotEqual(t, id, w.CommitteeID())
+	assert.Equal(t, w.CommitteeID(), fresh())
+
+	vk := w.VerificationKey()
+	assert.Equal(t, vk.CommitteeID(), w.CommitteeID())
+	assert.Equal(t, vk.verifier().CommitteeID(), w.CommitteeID())
+}
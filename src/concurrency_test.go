@@ -0,0 +1,64 @@
+package wts
+
+import (
+	"sync"
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+// Run with -race: several committees of different sizes combining and verifying at the same time
+func TestConcurrentCommittees(t *testing.T) {
+	msg := []byte("hello world")
+	sizes := []int{1 << 3, 1 << 4, 1 << 5}
+	rounds := 4
+
+	ws := make([]WTS, len(sizes))
+	for k, n := range sizes {
+		weights := make([]int, n)
+		for i := 0; i < n; i++ {
+			weights[i] = i + 1
+		}
+		ws[k] = NewWTS(n, weights, GenCRS(n))
+		ws[k].preProcess()
+	}
+
+	var wg sync.WaitGroup
+	for k := range ws {
+		w := &ws[k]
+		for r := 0; r < rounds; r++ {
+			wg.Add(1)
+			go func(r int) {
+				defer wg.Done()
+				var signers []int
+				var sigmas []bls.G2Jac
+				ths := 0
+				for i := r % 2; i < w.n; i += 2 {
+					signers = append(signers, i)
+					sigmas = append(sigmas, w.psign(msg, w.signers[i]))
+					ths += w.weights[i]
+				}
+				sig, err := w.combine(signers, sigmas)
+				assert.NoError(t, err)
+				assert.Equal(t, w.gverify(msg, sig, ths), true)
+			}(r)
+		}
+	}
+
+	// Fresh domains and zero slices are requested while the committees run
+	for m := 0; m < 8; m++ {
+		wg.Add(1)
+		go func(m int) {
+			defer wg.Done()
+			dom := GetDomain(uint64(1) << (m + 6))
+			assert.Equal(t, dom.Cardinality, uint64(1)<<(m+6))
+			z := GetZeros(4)
+			z[0].SetOne()
+		}(m)
+	}
+	wg.Wait()
+
+	z := GetZeros(4)
+	assert.Equal(t, z[0].IsZero(), true)
+}
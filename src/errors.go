@@ -0,0 +1,44 @@
+package wts
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Checks of Verify, in the order they are run
+var (
+	ErrInsufficientWeight = errors.New("wts: signature weight is below the threshold")
+	ErrAggSig             = errors.New("wts: aggregated BLS signature is invalid")
+	ErrAggPkDegree        = errors.New("wts: aggregated public key fails the degree check")
+	ErrBitvector          = errors.New("wts: bitvector commitments in G1 and G2 differ")
+	ErrBinary             = errors.New("wts: signer bitvector is not binary")
+	ErrInnerProduct       = errors.New("wts: inner-product proof is invalid")
+	ErrRTauDegree         = errors.New("wts: rTau fails the degree check")
+)
+
+// Malformed inputs of combine
+var (
+	ErrNoSigners       = errors.New("wts: empty signer set")
+	ErrSignerMismatch  = errors.New("wts: number of signers and partial signatures differ")
+	ErrSignerRange     = errors.New("wts: signer index out of range")
+	ErrDuplicateSigner = errors.New("wts: signer appears twice with different partial signatures")
+)
+
+// VerifyError reports the check that rejected a signature
+type VerifyError struct {
+	Err      error // The failed check of Verify
+	Claimed  int   // Weight claimed by the signature
+	Required int   // Weight required by the verifier
+	Dim      int   // Weight dimension of Claimed and Required, see VerifyMulti
+}
+
+func (e *VerifyError) Error() string {
+	if e.Dim > 0 {
+		return fmt.Sprintf("%v (claimed weight %d, required %d in dimension %d)", e.Err, e.Claimed, e.Required, e.Dim)
+	}
+	return fmt.Sprintf("%v (claimed weight %d, required %d)", e.Err, e.Claimed, e.Required)
+}
+
+func (e *VerifyError) Unwrap() error {
+	return e.Err
+}
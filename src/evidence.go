@@ -0,0 +1,176 @@
+package wts
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+const voteTag = "WTS-VOTE-V1"
+
+var (
+	ErrNotEquivocation = errors.New("wts: votes do not conflict")
+	ErrEvidence        = errors.New("wts: equivocation evidence is invalid")
+)
+
+// EquivocationSize is the length of an encoded Equivocation
+const EquivocationSize = 32 + 4 + bls.SizeOfG1AffineCompressed + 8 + 8 + 2*32 + 2*bls.SizeOfG2AffineCompressed
+
+// Vote is a partial signature on a payload at a height and round of a consensus protocol
+type Vote struct {
+	Height, Round uint64
+	Payload       [32]byte // SHA-256 of the payload
+	Partial       PartialSignature
+}
+
+// VoteMessage is the message signed by a vote: a tag, the height, the round and the payload digest
+func VoteMessage(height, round uint64, payload [32]byte) Message {
+	msg := append([]byte(voteTag), make([]byte, 16)...)
+	binary.BigEndian.PutUint64(msg[len(voteTag):], height)
+	binary.BigEndian.PutUint64(msg[len(voteTag)+8:], round)
+	return append(msg, payload[:]...)
+}
+
+// SignVote signs payload at height and round as the signer in slot
+func (w *WTS) SignVote(height, round uint64, payload []byte, slot int) (Vote, error) {
+	v := Vote{Height: height, Round: round, Payload: sha256.Sum256(payload)}
+	var err error
+	v.Partial, err = w.PartialSign(VoteMessage(height, round, v.Payload), slot)
+	return v, err
+}
+
+// Equivocation is slashable evidence that a signer voted for two payloads at the same
+// height and round. It carries the key of the signer, so it can be checked without the
+// committee; VerifyEquivocation also checks that the key is the one of the slot.
+type Equivocation struct {
+	Committee          CommitteeID
+	Slot               int
+	PubKey             bls.G1Affine
+	Height, Round      uint64
+	PayloadA, PayloadB [32]byte // PayloadA < PayloadB
+	SigA, SigB         bls.G2Affine
+}
+
+// NewEquivocation checks both votes with pverify and returns the evidence of their conflict
+func (w *WTS) NewEquivocation(a, b Vote) (Equivocation, error) {
+	if a.Height != b.Height || a.Round != b.Round || a.Partial.Slot != b.Partial.Slot || a.Payload == b.Payload {
+		return Equivocation{}, ErrNotEquivocation
+	}
+	cid := w.CommitteeID()
+	slot := a.Partial.Slot
+	if slot < 0 || slot >= w.n {
+		return Equivocation{}, fmt.Errorf("%w: %d not in [0, %d)", ErrSignerRange, slot, w.n)
+	}
+	vk := w.signers[slot].pKeyAff
+	for _, v := range []Vote{a, b} {
+		msg := VoteMessage(v.Height, v.Round, v.Payload)
+		if v.Partial.Committee != cid || v.Partial.Digest != sha256.Sum256(msg) {
+			return Equivocation{}, ErrPartialContext
+		}
+		roMsg, err := hashMsg(cid, msg)
+		if err != nil {
+			return Equivocation{}, err
+		}
+		if !w.pverify(roMsg, *new(bls.G2Jac).FromAffine(&v.Partial.Sigma), vk) {
+			return Equivocation{}, ErrPartialSig
+		}
+	}
+
+	if bytes.Compare(a.Payload[:], b.Payload[:]) > 0 {
+		a, b = b, a
+	}
+	return Equivocation{
+		Committee: cid,
+		Slot:      slot,
+		PubKey:    vk,
+		Height:    a.Height,
+		Round:     a.Round,
+		PayloadA:  a.Payload,
+		PayloadB:  b.Payload,
+		SigA:      a.Partial.Sigma,
+		SigB:      b.Partial.Sigma,
+	}, nil
+}
+
+// Verify checks that both signatures of e are valid under its key for distinct payloads
+func (e *Equivocation) Verify() error {
+	if bytes.Compare(e.PayloadA[:], e.PayloadB[:]) >= 0 {
+		return fmt.Errorf("%w: payloads are equal or out of order", ErrEvidence)
+	}
+	if e.PubKey.IsInfinity() {
+		return fmt.Errorf("%w: public key is the identity", ErrEvidence)
+	}
+	for _, v := range []struct {
+		payload [32]byte
+		sigma   bls.G2Affine
+	}{{e.PayloadA, e.SigA}, {e.PayloadB, e.SigB}} {
+		msg := VoteMessage(e.Height, e.Round, v.payload)
+		p := PartialSignature{Slot: e.Slot, Committee: e.Committee, Digest: sha256.Sum256(msg), Sigma: v.sigma}
+		if err := p.Verify(e.PubKey, msg); err != nil {
+			return fmt.Errorf("%w: %v", ErrEvidence, err)
+		}
+	}
+	return nil
+}
+
+// VerifyEquivocation checks e and that it names the key of a signer of the committee
+func (w *WTS) VerifyEquivocation(e *Equivocation) error {
+	if e.Committee != w.CommitteeID() {
+		return fmt.Errorf("%w: another committee", ErrEvidence)
+	}
+	if e.Slot < 0 || e.Slot >= w.n || !e.PubKey.Equal(&w.signers[e.Slot].pKeyAff) {
+		return fmt.Errorf("%w: key is not the one of slot %d", ErrEvidence, e.Slot)
+	}
+	return e.Verify()
+}
+
+// MarshalBinary encodes the evidence in EquivocationSize bytes
+func (e *Equivocation) MarshalBinary() ([]byte, error) {
+	if e.Slot < 0 || uint64(e.Slot) > uint64(^uint32(0)) {
+		return nil, fmt.Errorf("%w: slot %d", ErrEvidence, e.Slot)
+	}
+	buf := make([]byte, 0, EquivocationSize)
+	buf = append(buf, e.Committee[:]...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(e.Slot))
+	pk := e.PubKey.Bytes()
+	buf = append(buf, pk[:]...)
+	buf = binary.BigEndian.AppendUint64(buf, e.Height)
+	buf = binary.BigEndian.AppendUint64(buf, e.Round)
+	buf = append(buf, e.PayloadA[:]...)
+	buf = append(buf, e.PayloadB[:]...)
+	sigA, sigB := e.SigA.Bytes(), e.SigB.Bytes()
+	buf = append(buf, sigA[:]...)
+	return append(buf, sigB[:]...), nil
+}
+
+// UnmarshalBinary decodes an encoding of MarshalBinary, the points are checked to be in their groups
+func (e *Equivocation) UnmarshalBinary(data []byte) error {
+	if len(data) != EquivocationSize {
+		return fmt.Errorf("%w: %d bytes", ErrEvidence, len(data))
+	}
+	var dec Equivocation
+	off := copy(dec.Committee[:], data)
+	dec.Slot = int(binary.BigEndian.Uint32(data[off:]))
+	off += 4
+	if _, err := dec.PubKey.SetBytes(data[off : off+bls.SizeOfG1AffineCompressed]); err != nil {
+		return fmt.Errorf("%w: %v", ErrEvidence, err)
+	}
+	off += bls.SizeOfG1AffineCompressed
+	dec.Height = binary.BigEndian.Uint64(data[off:])
+	dec.Round = binary.BigEndian.Uint64(data[off+8:])
+	off += 16
+	off += copy(dec.PayloadA[:], data[off:])
+	off += copy(dec.PayloadB[:], data[off:])
+	for _, sigma := range []*bls.G2Affine{&dec.SigA, &dec.SigB} {
+		if _, err := sigma.SetBytes(data[off : off+bls.SizeOfG2AffineCompressed]); err != nil {
+			return fmt.Errorf("%w: %v", ErrEvidence, err)
+		}
+		off += bls.SizeOfG2AffineCompressed
+	}
+	*e = dec
+	return nil
+}
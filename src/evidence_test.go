@@ -0,0 +1,74 @@
+package wts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEquivocation(t *testing.T) {
+	n := 1 << 3
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+	w := NewWTS(n, weights, GenCRS(n))
+	w.preProcess()
+
+	slot := 3
+	a, err := w.SignVote(10, 2, []byte("block A"), slot)
+	assert.NoError(t, err)
+	b, err := w.SignVote(10, 2, []byte("block B"), slot)
+	assert.NoError(t, err)
+
+	ev, err := w.NewEquivocation(a, b)
+	assert.NoError(t, err)
+	assert.NoError(t, ev.Verify())
+	assert.NoError(t, w.VerifyEquivocation(&ev))
+
+	// The evidence does not depend on the order of the votes
+	ev2, err := w.NewEquivocation(b, a)
+	assert.NoError(t, err)
+	assert.Equal(t, ev2, ev)
+
+	enc, err := ev.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, len(enc), EquivocationSize)
+	var dec Equivocation
+	assert.NoError(t, dec.UnmarshalBinary(enc))
+	assert.Equal(t, dec, ev)
+	assert.NoError(t, dec.Verify())
+	assert.ErrorIs(t, dec.UnmarshalBinary(enc[1:]), ErrEvidence)
+
+	// Votes that do not conflict
+	later, err := w.SignVote(11, 0, []byte("block B"), slot)
+	assert.NoError(t, err)
+	other, err := w.SignVote(10, 2, []byte("block B"), slot+1)
+	assert.NoError(t, err)
+	for _, v := range []Vote{a, later, other} {
+		_, err = w.NewEquivocation(a, v)
+		assert.ErrorIs(t, err, ErrNotEquivocation)
+	}
+
+	// A forged vote is rejected
+	forged := b
+	forged.Partial.Sigma = other.Partial.Sigma
+	_, err = w.NewEquivocation(a, forged)
+	assert.ErrorIs(t, err, ErrPartialSig)
+
+	bad := ev
+	bad.SigB = bad.SigA
+	assert.ErrorIs(t, bad.Verify(), ErrEvidence)
+	bad = ev
+	bad.Height++
+	assert.ErrorIs(t, bad.Verify(), ErrEvidence)
+	bad = ev
+	bad.PubKey = w.signers[0].pKeyAff
+	assert.ErrorIs(t, bad.Verify(), ErrEvidence)
+	bad = ev
+	bad.Slot = 0
+	assert.ErrorIs(t, w.VerifyEquivocation(&bad), ErrEvidence)
+	bad = ev
+	bad.PayloadA, bad.PayloadB = ev.PayloadB, ev.PayloadA
+	assert.ErrorIs(t, bad.Verify(), ErrEvidence)
+}
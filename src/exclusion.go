@@ -0,0 +1,46 @@
+package wts
+
+import (
+	"errors"
+	"fmt"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+var (
+	ErrIsSigner       = errors.New("wts: index is in the signer set")
+	ErrExclusionProof = errors.New("wts: exclusion proof is invalid")
+)
+
+// ExclusionProof shows that signer Index is not in the signer set of a signature:
+// it is a KZG opening of the bitvector polynomial committed in bTau to b(omega^Index) = 0.
+type ExclusionProof struct {
+	Index int
+	pi    bls.G1Affine // [b(tau)/(tau-omega^Index)]
+}
+
+// ProveExclusion is run by the aggregator, signers is the signer set of the signature
+func (w *WTS) ProveExclusion(signers []int, idx int) (ExclusionProof, error) {
+	b, err := w.bitvector(signers)
+	if err != nil {
+		return ExclusionProof{}, err
+	}
+	if idx < 0 || idx >= w.n {
+		return ExclusionProof{}, fmt.Errorf("%w: %d not in [0, %d)", ErrSignerRange, idx, w.n)
+	}
+	if !b[idx].IsZero() {
+		return ExclusionProof{}, fmt.Errorf("%w: %d", ErrIsSigner, idx)
+	}
+	return ExclusionProof{Index: idx, pi: w.openBitvector(b, idx)}, nil
+}
+
+// VerifyExclusion checks pf against the bitvector commitment of sigma.
+// It does not verify sigma itself, evidence should also pass Verify, whose binary
+// check makes the committed bitvector a valid signer set.
+func (w *WTS) VerifyExclusion(sigma Sig, pf ExclusionProof) error {
+	if pf.Index < 0 || pf.Index >= w.n || !w.checkOpening(sigma.bTau, pf.Index, fr.NewElement(0), pf.pi) {
+		return ErrExclusionProof
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package wts
+
+import (
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExclusion(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 4
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+
+	w := NewWTS(n, weights, GenCRS(n))
+	w.preProcess()
+
+	signers := []int{1, 4, 9, 15}
+	sigmas := make([]bls.G2Jac, len(signers))
+	for i, idx := range signers {
+		sigmas[i] = w.psign(msg, w.signers[idx])
+	}
+	sig, err := w.combine(signers, sigmas)
+	assert.NoError(t, err)
+
+	for _, idx := range []int{0, 2, 3, 14} {
+		pf, err := w.ProveExclusion(signers, idx)
+		assert.NoError(t, err)
+		assert.NoError(t, w.VerifyExclusion(sig, pf))
+
+		// The opening does not carry over to a signer
+		pf.Index = 1
+		assert.ErrorIs(t, w.VerifyExclusion(sig, pf), ErrExclusionProof)
+	}
+
+	_, err = w.ProveExclusion(signers, 4)
+	assert.ErrorIs(t, err, ErrIsSigner)
+	_, err = w.ProveExclusion(signers, n)
+	assert.ErrorIs(t, err, ErrSignerRange)
+
+	// An inclusion proof cannot be passed off as an exclusion proof
+	in, err := w.ProveInclusion(signers, 9)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, w.VerifyExclusion(sig, ExclusionProof{Index: in.Index, pi: in.pi}), ErrExclusionProof)
+}
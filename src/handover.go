@@ -0,0 +1,278 @@
+package wts
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+const handoverTag = "WTS-HANDOVER-V1"
+
+var (
+	ErrVerificationKey = errors.New("wts: malformed verification key")
+	ErrHandover        = errors.New("wts: invalid committee handover")
+)
+
+// VerificationKey is what a verifier needs to check signatures of a committee:
+// the trapdoor dependent elements of the CRS, pComm, the weight commitments, n, the epoch,
+// the total weight and the registry digest, if any
+type VerificationKey struct {
+	n        int
+	epoch    uint64
+	total    int // Total weight of the first dimension
+	pComm    bls.G1Affine
+	wTau     bls.G1Affine
+	wTaus    []bls.G1Affine
+	registry *[32]byte
+
+	g1Ba, h1a                         bls.G1Affine
+	g2Ba, h2a, hTauHAff, g2Tau, vHTau bls.G2Affine
+}
+
+// VerificationKey returns the verification key of the committee in its current epoch
+func (w *WTS) VerificationKey() VerificationKey {
+	vk := VerificationKey{
+		n:        w.n,
+		epoch:    w.epoch,
+		pComm:    w.pp.pComm,
+		wTau:     w.pp.wTau,
+		wTaus:    w.pp.wTaus,
+		g1Ba:     w.crs.g1Ba,
+		h1a:      w.crs.h1a,
+		g2Ba:     w.crs.g2Ba,
+		h2a:      w.crs.h2a,
+		hTauHAff: w.crs.hTauHAff,
+		g2Tau:    w.crs.g2Tau,
+		vHTau:    w.crs.vHTau,
+	}
+	for _, wt := range w.weights {
+		vk.total += wt
+	}
+	if w.registryDigest != nil {
+		d := *w.registryDigest
+		vk.registry = &d
+	}
+	return vk
+}
+
+// Epoch is the epoch of the committee
+func (vk *VerificationKey) Epoch() uint64 {
+	return vk.epoch
+}
+
+// TotalWeight is the weight of all the signers of the committee
+func (vk *VerificationKey) TotalWeight() int {
+	return vk.total
+}
+
+// CommitteeID is the CommitteeID of the committee of vk
+func (vk *VerificationKey) CommitteeID() CommitteeID {
+	return committeeID(vk.crsID(), vk.pComm, vk.wTau, vk.wTaus, vk.n, vk.epoch, vk.registry)
+}
+
+func (vk *VerificationKey) crsID() [32]byte {
+	return crsID(vk.n, []bls.G1Affine{vk.g1Ba, vk.h1a}, []bls.G2Affine{vk.g2Ba, vk.h2a, vk.hTauHAff, vk.g2Tau, vk.vHTau})
+}
+
+// Verify is WTS.Verify for the committee of vk
+func (vk *VerificationKey) Verify(msg Message, sigma Sig, ths int) error {
+	return vk.verifier().Verify(msg, sigma, ths)
+}
+
+// A WTS holding just what Verify uses
+func (vk *VerificationKey) verifier() *WTS {
+	g1, g2, g1a, g2a := bls.Generators()
+	crsID := vk.crsID()
+	w := &WTS{
+		n:     vk.n,
+		epoch: vk.epoch,
+		crs: CRS{
+			g2:       g2,
+			g1a:      g1a,
+			g2a:      g2a,
+			g1InvAff: *new(bls.G1Affine).FromJacobian(new(bls.G1Jac).Neg(&g1)),
+			g2InvAff: *new(bls.G2Affine).FromJacobian(new(bls.G2Jac).Neg(&g2)),
+			g1Ba:     vk.g1Ba,
+			h1a:      vk.h1a,
+			g2Ba:     vk.g2Ba,
+			h2a:      vk.h2a,
+			hTauHAff: vk.hTauHAff,
+			g2Tau:    vk.g2Tau,
+			vHTau:    vk.vHTau,
+		},
+		pp:             Params{pComm: vk.pComm, wTau: vk.wTau, wTaus: vk.wTaus},
+		registryDigest: vk.registry,
+		crsID:          crsID,
+	}
+	w.updateCommitteeID()
+	return w
+}
+
+// MarshalBinary encodes n, the epoch, the total weight, the group elements in compressed
+// form, the number of extra weight commitments and the optional registry digest
+func (vk *VerificationKey) MarshalBinary() ([]byte, error) {
+	buf := binary.BigEndian.AppendUint32(nil, uint32(vk.n))
+	buf = binary.BigEndian.AppendUint64(buf, vk.epoch)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(vk.total))
+	for _, p := range []bls.G1Affine{vk.pComm, vk.wTau, vk.g1Ba, vk.h1a} {
+		b := p.Bytes()
+		buf = append(buf, b[:]...)
+	}
+	for _, p := range []bls.G2Affine{vk.g2Ba, vk.h2a, vk.hTauHAff, vk.g2Tau, vk.vHTau} {
+		b := p.Bytes()
+		buf = append(buf, b[:]...)
+	}
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(vk.wTaus)))
+	for _, p := range vk.wTaus {
+		b := p.Bytes()
+		buf = append(buf, b[:]...)
+	}
+	if vk.registry == nil {
+		return append(buf, 0), nil
+	}
+	buf = append(buf, 1)
+	return append(buf, vk.registry[:]...), nil
+}
+
+// UnmarshalBinary decodes an encoding of MarshalBinary, the points are checked to be in their groups
+func (vk *VerificationKey) UnmarshalBinary(data []byte) error {
+	const g1Size, g2Size = bls.SizeOfG1AffineCompressed, bls.SizeOfG2AffineCompressed
+	fixed := 4 + 8 + 8 + 4*g1Size + 5*g2Size + 4
+	if len(data) < fixed+1 {
+		return fmt.Errorf("%w: %d bytes", ErrVerificationKey, len(data))
+	}
+	var dec VerificationKey
+	dec.n = int(binary.BigEndian.Uint32(data))
+	dec.epoch = binary.BigEndian.Uint64(data[4:])
+	total := binary.BigEndian.Uint64(data[12:])
+	if total > uint64(^uint(0)>>1) {
+		return fmt.Errorf("%w: total weight %d", ErrVerificationKey, total)
+	}
+	dec.total = int(total)
+	off := 20
+	for _, p := range []*bls.G1Affine{&dec.pComm, &dec.wTau, &dec.g1Ba, &dec.h1a} {
+		if _, err := p.SetBytes(data[off : off+g1Size]); err != nil {
+			return fmt.Errorf("%w: %v", ErrVerificationKey, err)
+		}
+		off += g1Size
+	}
+	for _, p := range []*bls.G2Affine{&dec.g2Ba, &dec.h2a, &dec.hTauHAff, &dec.g2Tau, &dec.vHTau} {
+		if _, err := p.SetBytes(data[off : off+g2Size]); err != nil {
+			return fmt.Errorf("%w: %v", ErrVerificationKey, err)
+		}
+		off += g2Size
+	}
+	dims := int(binary.BigEndian.Uint32(data[off:]))
+	off += 4
+	if dims > (len(data)-off-1)/g1Size {
+		return fmt.Errorf("%w: truncated weight commitments", ErrVerificationKey)
+	}
+	dec.wTaus = make([]bls.G1Affine, dims)
+	for d := range dec.wTaus {
+		if _, err := dec.wTaus[d].SetBytes(data[off : off+g1Size]); err != nil {
+			return fmt.Errorf("%w: %v", ErrVerificationKey, err)
+		}
+		off += g1Size
+	}
+	switch {
+	case data[off] == 0 && len(data) == off+1:
+	case data[off] == 1 && len(data) == off+33:
+		dec.registry = new([32]byte)
+		copy(dec.registry[:], data[off+1:])
+	default:
+		return fmt.Errorf("%w: bad registry digest", ErrVerificationKey)
+	}
+	*vk = dec
+	return nil
+}
+
+// Handover certifies the verification key of the committee of the next epoch with a
+// signature of the current committee on HandoverMessage(Next)
+type Handover struct {
+	Next VerificationKey
+	Sig  Sig
+}
+
+// MarshalBinary encodes the length of the encoded verification key, the key and the signature
+func (h *Handover) MarshalBinary() ([]byte, error) {
+	vk, err := h.Next.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := h.Sig.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf := binary.BigEndian.AppendUint32(nil, uint32(len(vk)))
+	buf = append(buf, vk...)
+	return append(buf, sig...), nil
+}
+
+// UnmarshalBinary decodes an encoding of MarshalBinary, see VerificationKey.UnmarshalBinary
+// and Sig.UnmarshalBinary for the checks
+func (h *Handover) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 || uint64(len(data)-4) != uint64(binary.BigEndian.Uint32(data))+SigSize {
+		return fmt.Errorf("%w: %d bytes", ErrHandover, len(data))
+	}
+	var dec Handover
+	off := len(data) - SigSize
+	if err := dec.Next.UnmarshalBinary(data[4:off]); err != nil {
+		return err
+	}
+	if err := dec.Sig.UnmarshalBinary(data[off:]); err != nil {
+		return err
+	}
+	*h = dec
+	return nil
+}
+
+// HandoverMessage is the message a committee signs to hand over to next
+func HandoverMessage(next *VerificationKey) (Message, error) {
+	enc, err := next.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(enc)
+	return append([]byte(handoverTag), h[:]...), nil
+}
+
+// CertifyHandover combines partial signatures on HandoverMessage(next) into a Handover
+func (w *WTS) CertifyHandover(next VerificationKey, signers []int, sigmas []bls.G2Jac) (Handover, error) {
+	if next.epoch != w.epoch+1 {
+		return Handover{}, fmt.Errorf("%w: epoch %d does not follow %d", ErrHandover, next.epoch, w.epoch)
+	}
+	sig, err := w.combine(signers, sigmas)
+	if err != nil {
+		return Handover{}, err
+	}
+	return Handover{Next: next, Sig: sig}, nil
+}
+
+// SyncCommittees follows a chain of handovers from a trusted committee and returns the
+// verification key of the last one. Every handover is signed by the previous committee
+// with at least num/den of its total weight, and moves to the next epoch.
+func SyncCommittees(trusted VerificationKey, chain []Handover, num, den int) (VerificationKey, error) {
+	if num <= 0 || den <= 0 || num > den {
+		return VerificationKey{}, fmt.Errorf("%w: threshold %d/%d", ErrHandover, num, den)
+	}
+	cur := trusted
+	for i := range chain {
+		h := &chain[i]
+		if h.Next.epoch != cur.epoch+1 {
+			return VerificationKey{}, fmt.Errorf("%w: handover %d goes from epoch %d to %d", ErrHandover, i, cur.epoch, h.Next.epoch)
+		}
+		msg, err := HandoverMessage(&h.Next)
+		if err != nil {
+			return VerificationKey{}, err
+		}
+		ths := (cur.total*num + den - 1) / den
+		if err := cur.Verify(msg, h.Sig, ths); err != nil {
+			return VerificationKey{}, fmt.Errorf("wts: handover %d: %w", i, err)
+		}
+		cur = h.Next
+	}
+	return cur, nil
+}
@@ -0,0 +1,97 @@
+package wts
+
+import (
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandoverChain(t *testing.T) {
+	n := 1 << 3
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+	epochs := 3
+
+	// A fresh committee per epoch, the last one has a registry
+	committees := make([]WTS, epochs+1)
+	for e := range committees {
+		committees[e] = NewWTS(n, weights, GenCRS(n))
+		committees[e].SetEpoch(uint64(e))
+		committees[e].preProcess()
+	}
+	r, err := NewRegistry([]string{"a", "b", "c"}, n)
+	assert.NoError(t, err)
+	assert.NoError(t, committees[epochs].SetRegistry(r))
+
+	// Each committee signs the next one with the signers 2..n-1, i.e. 33 of 36
+	var chain []Handover
+	for e := 0; e < epochs; e++ {
+		w := &committees[e]
+		next := committees[e+1].VerificationKey()
+		msg, err := HandoverMessage(&next)
+		assert.NoError(t, err)
+		signers := GetRange(2, n)
+		sigmas := make([]bls.G2Jac, len(signers))
+		for i, idx := range signers {
+			sigmas[i] = w.psign(msg, w.signers[idx])
+		}
+		h, err := w.CertifyHandover(next, signers, sigmas)
+		assert.NoError(t, err)
+		chain = append(chain, h)
+	}
+
+	genesis := committees[0].VerificationKey()
+	enc, err := genesis.MarshalBinary()
+	assert.NoError(t, err)
+	var trusted VerificationKey
+	assert.NoError(t, trusted.UnmarshalBinary(enc))
+	assert.Equal(t, trusted.CommitteeID(), committees[0].CommitteeID())
+
+	// Handovers as a light client receives them
+	received := make([]Handover, len(chain))
+	for i := range chain {
+		enc, err := chain[i].MarshalBinary()
+		assert.NoError(t, err)
+		assert.NoError(t, received[i].UnmarshalBinary(enc))
+		assert.ErrorIs(t, new(Handover).UnmarshalBinary(enc[:len(enc)-1]), ErrHandover)
+	}
+
+	head, err := SyncCommittees(trusted, received, 2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, head.Epoch(), uint64(epochs))
+	assert.Equal(t, head.CommitteeID(), committees[epochs].CommitteeID())
+	assert.Equal(t, head.TotalWeight(), 36)
+
+	enc, err = head.MarshalBinary()
+	assert.NoError(t, err)
+	var dec VerificationKey
+	assert.NoError(t, dec.UnmarshalBinary(enc))
+	assert.Equal(t, dec, head)
+	assert.ErrorIs(t, dec.UnmarshalBinary(enc[:len(enc)-1]), ErrVerificationKey)
+
+	// The head committee signs with its verification key
+	msg := []byte("hello world")
+	w := &committees[epochs]
+	sigmas := []bls.G2Jac{w.psign(msg, w.signers[0]), w.psign(msg, w.signers[7])}
+	sig, err := w.combine([]int{0, 7}, sigmas)
+	assert.NoError(t, err)
+	assert.NoError(t, head.Verify(msg, sig, 9))
+
+	// 33 of 36 is short of 95%
+	_, err = SyncCommittees(trusted, chain, 95, 100)
+	assert.ErrorIs(t, err, ErrInsufficientWeight)
+	// A chain with a gap, or a certificate for another key
+	_, err = SyncCommittees(trusted, chain[1:], 2, 3)
+	assert.ErrorIs(t, err, ErrHandover)
+	bad := append([]Handover(nil), chain...)
+	bad[1].Next = committees[1].VerificationKey()
+	bad[1].Next.epoch = 2
+	_, err = SyncCommittees(trusted, bad, 2, 3)
+	assert.ErrorIs(t, err, ErrAggSig)
+
+	_, err = committees[0].CertifyHandover(committees[2].VerificationKey(), []int{0}, sigmas[:1])
+	assert.ErrorIs(t, err, ErrHandover)
+}
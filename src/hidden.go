@@ -0,0 +1,117 @@
+package wts
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+var ErrRangeProof = errors.New("wts: range proof on the hidden weight is invalid")
+
+// HiddenWeightSig proves that the signers weigh at least Threshold without revealing their weight.
+//
+// The weight t is replaced by a Pedersen commitment C = t.g + rho.[tau] in the transcript and in
+// the inner-product check, where mu = aggPk + xi.C. The extra xi.rho.[tau]/n is absorbed in rTau,
+// and a range proof shows that C - Threshold.g commits to a value in [0, 2^k), with 2^k above
+// the total weight of the committee.
+//
+// Only the weight is hidden: bTau and aggPk still determine the signer set.
+type HiddenWeightSig struct {
+	Sig       // ths is left to zero
+	Threshold int
+	wComm     bls.G1Affine // C
+	rangePf   RangeProof
+}
+
+// CombineHidden is combine for a HiddenWeightSig proving a weight of at least ths
+func (w *WTS) CombineHidden(signers []int, sigmas []bls.G2Jac, ths int) (HiddenWeightSig, error) {
+	parts, err := w.combineParts(signers, sigmas)
+	if err != nil {
+		return HiddenWeightSig{}, err
+	}
+	if ths < 0 || parts.weight < ths {
+		return HiddenWeightSig{}, fmt.Errorf("%w: weight %d, threshold %d", ErrInsufficientWeight, parts.weight, ths)
+	}
+
+	rho, err := RandomElement(w.rnd)
+	if err != nil {
+		return HiddenWeightSig{}, err
+	}
+	var wComm, rhoH bls.G1Affine
+	wComm.ScalarMultiplication(&w.crs.g1a, big.NewInt(int64(parts.weight)))
+	rhoH.ScalarMultiplication(&w.crs.PoT[1], rho.BigInt(&big.Int{}))
+	wComm.Add(&wComm, &rhoH)
+
+	cid := w.CommitteeID()
+	xi := w.getFSChal(cid, []bls.G1Affine{w.pp.pComm, w.pp.wTau, parts.bTau, parts.aggPk, wComm}, ths)
+	sig := parts.sig(xi)
+	sig.ths = 0
+
+	// rTau - xi.rho/n.g
+	nInv := fr.NewElement(uint64(w.n))
+	nInv.Inverse(&nInv)
+	var shift fr.Element
+	shift.Mul(&xi, &rho).Mul(&shift, &nInv)
+	var shiftG bls.G1Affine
+	shiftG.ScalarMultiplication(&w.crs.g1a, shift.BigInt(&big.Int{}))
+	sig.pi.rTau.Sub(&sig.pi.rTau, &shiftG)
+
+	pf, err := proveRange(w.crs.g1a, w.crs.PoT[1], uint64(parts.weight-ths), rho, w.weightBits(),
+		w.hiddenTranscript(cid, wComm, ths), w.rnd)
+	if err != nil {
+		return HiddenWeightSig{}, err
+	}
+
+	return HiddenWeightSig{
+		Sig:       sig,
+		Threshold: ths,
+		wComm:     wComm,
+		rangePf:   pf,
+	}, nil
+}
+
+// VerifyHidden checks that sigma is a signature on msg of weight at least ths
+func (w *WTS) VerifyHidden(msg Message, sigma HiddenWeightSig, ths int) error {
+	fail := func(err error) error {
+		return &VerifyError{Err: err, Claimed: sigma.Threshold, Required: ths}
+	}
+	if sigma.Threshold < ths {
+		return fail(ErrInsufficientWeight)
+	}
+
+	cid := w.CommitteeID()
+	var excess, thsG bls.G1Affine
+	thsG.ScalarMultiplication(&w.crs.g1a, big.NewInt(int64(sigma.Threshold)))
+	excess.Sub(&sigma.wComm, &thsG)
+	if !verifyRange(w.crs.g1a, w.crs.PoT[1], excess, w.weightBits(), sigma.rangePf,
+		w.hiddenTranscript(cid, sigma.wComm, sigma.Threshold)) {
+		return fail(ErrRangeProof)
+	}
+
+	xi := w.getFSChal(cid, []bls.G1Affine{w.pp.pComm, w.pp.wTau, sigma.bTau, sigma.aggPk, sigma.wComm}, sigma.Threshold)
+	return w.verifyChecks(cid, msg, sigma.Sig, xi, []bls.G1Affine{sigma.wComm}, fail)
+}
+
+// Number of bits of the total weight of the committee
+func (w *WTS) weightBits() int {
+	total := 0
+	for _, wt := range w.weights {
+		total += wt
+	}
+	if total == 0 {
+		return 1
+	}
+	return bits.Len(uint(total))
+}
+
+func (w *WTS) hiddenTranscript(cid CommitteeID, wComm bls.G1Affine, ths int) []byte {
+	c := wComm.Bytes()
+	tr := append([]byte{}, cid[:]...)
+	tr = append(tr, c[:]...)
+	return binary.BigEndian.AppendUint64(tr, uint64(ths))
+}
@@ -0,0 +1,78 @@
+package wts
+
+import (
+	"math/big"
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangeProof(t *testing.T) {
+	_, _, g, _ := bls.Generators()
+	var h bls.G1Affine
+	h.ScalarMultiplication(&g, big.NewInt(7))
+
+	transcript := []byte("range")
+	rnd := NewSeededReader([]byte("range"))
+	for _, v := range []uint64{0, 1, 5, 255} {
+		rho, _ := RandomElement(rnd)
+		var comm, rhoH bls.G1Affine
+		comm.ScalarMultiplication(&g, new(big.Int).SetUint64(v))
+		rhoH.ScalarMultiplication(&h, rho.BigInt(&big.Int{}))
+		comm.Add(&comm, &rhoH)
+
+		pf, err := proveRange(g, h, v, rho, 8, transcript, rnd)
+		assert.NoError(t, err)
+		assert.Equal(t, verifyRange(g, h, comm, 8, pf, transcript), true)
+		assert.Equal(t, verifyRange(g, h, comm, 8, pf, []byte("other")), false)
+		assert.Equal(t, verifyRange(g, h, rhoH, 8, pf, transcript), v == 0)
+	}
+
+	_, err := proveRange(g, h, 256, fr.One(), 8, transcript, rnd)
+	assert.Error(t, err)
+}
+
+func TestHiddenWeight(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 4
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+
+	w := NewWTS(n, weights, GenCRS(n))
+	w.preProcess()
+
+	signers := []int{1, 4, 9, 15}
+	sigmas := make([]bls.G2Jac, len(signers))
+	weight := 0
+	for i, idx := range signers {
+		sigmas[i] = w.psign(msg, w.signers[idx])
+		weight += weights[idx]
+	}
+
+	for _, ths := range []int{0, weight / 2, weight} {
+		sig, err := w.CombineHidden(signers, sigmas, ths)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, sig.ths)
+		assert.NoError(t, w.VerifyHidden(msg, sig, ths))
+		assert.ErrorIs(t, w.VerifyHidden(msg, sig, ths+1), ErrInsufficientWeight)
+		assert.ErrorIs(t, w.VerifyHidden([]byte("other message"), sig, ths), ErrAggSig)
+
+		// Raising the claimed threshold breaks the range proof
+		bad := sig
+		bad.Threshold = weight + 1
+		assert.ErrorIs(t, w.VerifyHidden(msg, bad, ths), ErrRangeProof)
+
+		// and a commitment to another weight breaks the inner product
+		bad = sig
+		bad.wComm.Add(&bad.wComm, &w.crs.g1a)
+		bad.rangePf.bits[0].Add(&bad.rangePf.bits[0], &w.crs.g1a)
+		assert.Error(t, w.VerifyHidden(msg, bad, ths))
+	}
+
+	_, err := w.CombineHidden(signers, sigmas, weight+1)
+	assert.ErrorIs(t, err, ErrInsufficientWeight)
+}
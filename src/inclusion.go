@@ -0,0 +1,90 @@
+package wts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+)
+
+var (
+	ErrNotSigner      = errors.New("wts: index is not in the signer set")
+	ErrInclusionProof = errors.New("wts: inclusion proof is invalid")
+)
+
+// InclusionProof shows that signer Index is in the signer set of a signature:
+// it is a KZG opening of the bitvector polynomial committed in bTau to b(omega^Index) = 1.
+type InclusionProof struct {
+	Index int
+	pi    bls.G1Affine // [(b(tau)-1)/(tau-omega^Index)]
+}
+
+// ProveInclusion is run by the aggregator, signers is the signer set of the signature
+func (w *WTS) ProveInclusion(signers []int, idx int) (InclusionProof, error) {
+	b, err := w.bitvector(signers)
+	if err != nil {
+		return InclusionProof{}, err
+	}
+	if idx < 0 || idx >= w.n || b[idx].IsZero() {
+		return InclusionProof{}, fmt.Errorf("%w: %d", ErrNotSigner, idx)
+	}
+	return InclusionProof{Index: idx, pi: w.openBitvector(b, idx)}, nil
+}
+
+// VerifyInclusion checks pf against the bitvector commitment of sigma.
+// It does not verify sigma itself.
+func (w *WTS) VerifyInclusion(sigma Sig, pf InclusionProof) error {
+	if pf.Index < 0 || pf.Index >= w.n || !w.checkOpening(sigma.bTau, pf.Index, fr.One(), pf.pi) {
+		return ErrInclusionProof
+	}
+	return nil
+}
+
+// Evaluations over H of the bitvector of signers
+func (w *WTS) bitvector(signers []int) ([]fr.Element, error) {
+	b := make([]fr.Element, w.n)
+	for _, idx := range signers {
+		if idx < 0 || idx >= w.n {
+			return nil, fmt.Errorf("%w: %d not in [0, %d)", ErrSignerRange, idx, w.n)
+		}
+		b[idx].SetOne()
+	}
+	return b, nil
+}
+
+// KZG opening at omega^i of the polynomial with evaluations b over H
+func (w *WTS) openBitvector(b []fr.Element, i int) bls.G1Affine {
+	coeffs := make([]fr.Element, w.n)
+	copy(coeffs, b)
+	w.crs.domain.FFTInverse(coeffs, fft.DIF)
+	fft.BitReverse(coeffs)
+
+	// (b(X) - b(z))/(X - z) by synthetic division
+	z := w.crs.H[i]
+	q := make([]fr.Element, w.n-1)
+	q[w.n-2] = coeffs[w.n-1]
+	for k := w.n - 2; k > 0; k-- {
+		q[k-1].Mul(&q[k], &z).Add(&q[k-1], &coeffs[k])
+	}
+
+	pi, _ := new(bls.G1Affine).MultiExp(w.crs.PoT[:w.n-1], q, ecc.MultiExpConfig{})
+	return *pi
+}
+
+// Checks e(comm - [v], g2) = e(pi, [tau - omega^i]_2)
+func (w *WTS) checkOpening(comm bls.G1Affine, i int, v fr.Element, pi bls.G1Affine) bool {
+	var lhs bls.G1Affine
+	lhs.ScalarMultiplication(&w.crs.g1a, v.BigInt(&big.Int{}))
+	lhs.Sub(&comm, &lhs)
+
+	var zG2 bls.G2Affine
+	zG2.ScalarMultiplication(&w.crs.g2a, w.crs.H[i].BigInt(&big.Int{}))
+	zG2.Sub(&w.crs.g2Tau, &zG2)
+
+	res, _ := bls.PairingCheck([]bls.G1Affine{lhs, pi}, []bls.G2Affine{w.crs.g2InvAff, zG2})
+	return res
+}
@@ -0,0 +1,52 @@
+package wts
+
+import (
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInclusion(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 4
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+
+	w := NewWTS(n, weights, GenCRS(n))
+	w.preProcess()
+
+	signers := []int{1, 4, 9, 15}
+	sigmas := make([]bls.G2Jac, len(signers))
+	for i, idx := range signers {
+		sigmas[i] = w.psign(msg, w.signers[idx])
+	}
+	sig, err := w.combine(signers, sigmas)
+	assert.NoError(t, err)
+
+	for _, idx := range signers {
+		pf, err := w.ProveInclusion(signers, idx)
+		assert.NoError(t, err)
+		assert.NoError(t, w.VerifyInclusion(sig, pf))
+
+		// The proof is bound to the index
+		pf.Index = (idx + 1) % n
+		assert.ErrorIs(t, w.VerifyInclusion(sig, pf), ErrInclusionProof)
+	}
+
+	_, err = w.ProveInclusion(signers, 2)
+	assert.ErrorIs(t, err, ErrNotSigner)
+	_, err = w.ProveInclusion(signers, n)
+	assert.ErrorIs(t, err, ErrNotSigner)
+	_, err = w.ProveInclusion([]int{n}, 0)
+	assert.ErrorIs(t, err, ErrSignerRange)
+
+	// A proof for another signature does not verify
+	other, err := w.combine(signers[:2], sigmas[:2])
+	assert.NoError(t, err)
+	pf, err := w.ProveInclusion(signers, 15)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, w.VerifyInclusion(other, pf), ErrInclusionProof)
+}
@@ -0,0 +1,173 @@
+package wts
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Hierarchical derivation of BLS12-381 signing keys following EIP-2333,
+// with paths in the EIP-2334 format "m/12381/3600/i/0/0".
+
+const (
+	keyGenSalt     = "BLS-SIG-KEYGEN-SALT-"
+	lamportChunks  = 255
+	lamportChunkSz = sha256.Size
+	minSeedLen     = 32
+)
+
+var (
+	ErrShortSeed   = errors.New("wts: seed must be at least 32 bytes")
+	ErrInvalidPath = errors.New("wts: invalid derivation path")
+)
+
+// SeedFromMnemonic turns a BIP-39 mnemonic into a seed using PBKDF2-HMAC-SHA512.
+// The mnemonic is used as given; word list validation and NFKD normalisation are left to the caller.
+func SeedFromMnemonic(mnemonic, passphrase string) []byte {
+	return pbkdf2.Key([]byte(mnemonic), []byte("mnemonic"+passphrase), 2048, 64, sha512.New)
+}
+
+// DeriveMasterSK returns the EIP-2333 master secret key for the given seed
+func DeriveMasterSK(seed []byte) (fr.Element, error) {
+	if len(seed) < minSeedLen {
+		return fr.Element{}, ErrShortSeed
+	}
+	return hkdfModR(seed, nil), nil
+}
+
+// DeriveChildSK returns the EIP-2333 child secret key of parent at the given index
+func DeriveChildSK(parent fr.Element, index uint32) fr.Element {
+	return hkdfModR(parentSKToLamportPK(parent, index), nil)
+}
+
+// DeriveSK derives the secret key at path, e.g. "m/12381/3600/0/0/0", from the seed
+func DeriveSK(seed []byte, path string) (fr.Element, error) {
+	indices, err := parsePath(path)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	sk, err := DeriveMasterSK(seed)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	for _, idx := range indices {
+		sk = DeriveChildSK(sk, idx)
+	}
+	return sk, nil
+}
+
+// SignerKeyPath is the EIP-2334 signing key path of the i-th signer
+func SignerKeyPath(i int) string {
+	return fmt.Sprintf("m/12381/3600/%d/0/0", i)
+}
+
+// DeriveSignerKeys derives the signing keys of n signers from a single seed using SignerKeyPath
+func DeriveSignerKeys(seed []byte, n int) ([]fr.Element, error) {
+	master, err := DeriveMasterSK(seed)
+	if err != nil {
+		return nil, err
+	}
+	// m/12381/3600 is shared by all the signers
+	base := DeriveChildSK(DeriveChildSK(master, 12381), 3600)
+
+	sKeys := make([]fr.Element, n)
+	parallelFor(n, func(i int) {
+		sKeys[i] = signerKey(base, i)
+	})
+	return sKeys, nil
+}
+
+// DeriveSignerKey derives the signing key of the i-th signer only, as DeriveSignerKeys does
+func DeriveSignerKey(seed []byte, i int) (fr.Element, error) {
+	master, err := DeriveMasterSK(seed)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	return signerKey(DeriveChildSK(DeriveChildSK(master, 12381), 3600), i), nil
+}
+
+// Derives i/0/0 from the key at m/12381/3600
+func signerKey(base fr.Element, i int) fr.Element {
+	sk := DeriveChildSK(base, uint32(i))
+	sk = DeriveChildSK(sk, 0)
+	return DeriveChildSK(sk, 0)
+}
+
+func parsePath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) == 0 || parts[0] != "m" {
+		return nil, ErrInvalidPath
+	}
+	indices := make([]uint32, len(parts)-1)
+	for i, p := range parts[1:] {
+		idx, err := strconv.ParseUint(p, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPath, path)
+		}
+		indices[i] = uint32(idx)
+	}
+	return indices, nil
+}
+
+// HKDF_mod_r from EIP-2333
+func hkdfModR(ikm, keyInfo []byte) fr.Element {
+	const L = 48
+	salt := []byte(keyGenSalt)
+	ikmPad := append(append([]byte{}, ikm...), 0)
+	info := append(append([]byte{}, keyInfo...), 0, L)
+
+	r := fr.Modulus()
+	okm := make([]byte, L)
+	sk := new(big.Int)
+	for sk.Sign() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		prk := hkdf.Extract(sha256.New, ikmPad, salt)
+		if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, info), okm); err != nil {
+			panic(err) // L is far below the HKDF output limit
+		}
+		sk.SetBytes(okm).Mod(sk, r)
+	}
+	return *new(fr.Element).SetBigInt(sk)
+}
+
+func ikmToLamportSK(ikm, salt []byte) []byte {
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	okm := make([]byte, lamportChunks*lamportChunkSz)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, nil), okm); err != nil {
+		panic(err) // 255 blocks is exactly the HKDF output limit
+	}
+	return okm
+}
+
+func parentSKToLamportPK(parent fr.Element, index uint32) []byte {
+	salt := make([]byte, 4)
+	binary.BigEndian.PutUint32(salt, index)
+
+	ikm := parent.Bytes()
+	notIKM := ikm
+	for i := range notIKM {
+		notIKM[i] ^= 0xff
+	}
+	lamport0 := ikmToLamportSK(ikm[:], salt)
+	lamport1 := ikmToLamportSK(notIKM[:], salt)
+
+	hFunc := sha256.New()
+	for _, lamport := range [][]byte{lamport0, lamport1} {
+		for i := 0; i < lamportChunks; i++ {
+			chunk := sha256.Sum256(lamport[i*lamportChunkSz : (i+1)*lamportChunkSz])
+			hFunc.Write(chunk[:])
+		}
+	}
+	return hFunc.Sum(nil)
+}
@@ -0,0 +1,153 @@
+package wts
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test vectors from EIP-2333
+var eip2333Vectors = []struct {
+	seed     string
+	masterSK string
+	index    uint32
+	childSK  string
+}{
+	{
+		"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		"6083874454709270928345386274498605044986640685124978867557563392430687146096",
+		0,
+		"20397789859736650942317412262472558107875392172444076792671091975210932703118",
+	},
+	{
+		"3141592653589793238462643383279502884197169399375105820974944592",
+		"29757020647961307431480504535336562678282505419141012933316116377660817309383",
+		3141592653,
+		"25457201688850691947727629385191704516744796114925897962676248250929345014287",
+	},
+	{
+		"0099FF991111002299DD7744EE3355BBDD8844115566CC55663355668888CC00",
+		"27580842291869792442942448775674722299803720648445448686099262467207037398656",
+		4294967295,
+		"29358610794459428860402234341874281240803786294062035874021252734817515685787",
+	},
+	{
+		"d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+		"19022158461524446591288038168518313374041767046816487870552872741050760015818",
+		42,
+		"31372231650479070279774297061823572166496564838472787488249775572789064611981",
+	},
+}
+
+func TestEIP2333Vectors(t *testing.T) {
+	for _, tc := range eip2333Vectors {
+		seed, err := hex.DecodeString(tc.seed)
+		assert.NoError(t, err)
+
+		master, err := DeriveMasterSK(seed)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.masterSK, master.BigInt(new(big.Int)).String())
+
+		child := DeriveChildSK(master, tc.index)
+		assert.Equal(t, tc.childSK, child.BigInt(new(big.Int)).String())
+	}
+}
+
+func TestDeriveSK(t *testing.T) {
+	seed := SeedFromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	assert.Equal(t, 64, len(seed))
+
+	_, err := DeriveMasterSK(seed[:16])
+	assert.ErrorIs(t, err, ErrShortSeed)
+	_, err = DeriveSK(seed, "12381/3600/0/0/0")
+	assert.ErrorIs(t, err, ErrInvalidPath)
+	_, err = DeriveSK(seed, "m/12381/x")
+	assert.ErrorIs(t, err, ErrInvalidPath)
+
+	n := 8
+	sKeys, err := DeriveSignerKeys(seed, n)
+	assert.NoError(t, err)
+	for i := 0; i < n; i++ {
+		sk, err := DeriveSK(seed, SignerKeyPath(i))
+		assert.NoError(t, err)
+		assert.Equal(t, sk.Equal(&sKeys[i]), true)
+	}
+}
+
+func TestNewWTSFromSeed(t *testing.T) {
+	n := 1 << 4
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i
+	}
+	seed := make([]byte, 32)
+
+	crs := GenCRS(n)
+	w1, err := NewWTSFromSeed(n, weights, crs, seed)
+	assert.NoError(t, err)
+	w2, err := NewWTSFromSeed(n, weights, crs, seed)
+	assert.NoError(t, err)
+	assert.Equal(t, w1.pp.pComm.Equal(&w2.pp.pComm), true)
+
+	var pk bls.G1Affine
+	for i := 0; i < n; i++ {
+		sk, _ := DeriveSK(seed, SignerKeyPath(i))
+		pk.ScalarMultiplication(&crs.g1a, sk.BigInt(&big.Int{}))
+		assert.Equal(t, pk.Equal(&w1.signers[i].pKeyAff), true)
+	}
+
+	// Recovering keys from the seed gives a working committee
+	msg := []byte("hello world")
+	w1.preProcess()
+	signers := GetRangeTo(n)
+	sigmas := make([]bls.G2Jac, n)
+	ths := 0
+	for i := 0; i < n; i++ {
+		sigmas[i] = w1.psign(msg, w1.signers[i])
+		ths += weights[i]
+	}
+	sig, err := w1.combine(signers, sigmas)
+	assert.NoError(t, err)
+	assert.Equal(t, w1.gverify(msg, sig, ths), true)
+}
+
+func TestSignerHintsFromSeed(t *testing.T) {
+	n := 1 << 3
+	weights := make([]int, n)
+	seed := make([]byte, 32)
+	crs := GenCRS(n)
+	w, err := NewWTSFromSeed(n, weights, crs, seed)
+	assert.NoError(t, err)
+
+	for i := 0; i < n; i++ {
+		hints, err := crs.SignerHintsFromSeed(seed, i)
+		assert.NoError(t, err)
+		hTauH := *new(bls.G1Affine).FromJacobian(&w.pp.hTausH[i])
+		assert.Equal(t, hints.PKey.Equal(&w.pp.pKeys[i]), true)
+		assert.Equal(t, hints.PKeyB.Equal(&w.pp.pKeysB[i]), true)
+		assert.Equal(t, hints.ATau.Equal(&w.pp.aTaus[i]), true)
+		assert.Equal(t, hints.HTau.Equal(&w.pp.hTaus[i]), true)
+		assert.Equal(t, hints.HTauH.Equal(&hTauH), true)
+		assert.Equal(t, len(hints.LTaus), n-1)
+		for l := range hints.LTaus {
+			assert.Equal(t, hints.LTaus[l].Equal(&w.pp.lTaus[l][i]), true)
+		}
+	}
+
+	_, err = crs.SignerHintsFromSeed(seed, n)
+	assert.ErrorIs(t, err, ErrSignerRange)
+	_, err = crs.SignerHintsFromSeed(seed[:16], 0)
+	assert.ErrorIs(t, err, ErrShortSeed)
+}
+
+func BenchmarkDeriveChildSK(b *testing.B) {
+	var sk fr.Element
+	sk.SetRandom()
+	for i := 0; i < b.N; i++ {
+		DeriveChildSK(sk, uint32(i))
+	}
+}
@@ -0,0 +1,128 @@
+package wts
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+var ErrWeightDims = errors.New("wts: weight dimensions do not match the committee")
+
+// NewMultiWeightWTS is NewWTS for a committee with several weight vectors, e.g. stake and
+// headcount. weights[0] is the weight distribution used by combine and Verify.
+func NewMultiWeightWTS(n int, weights [][]int, crs CRS) (WTS, error) {
+	return NewMultiWeightWTSWithRand(n, weights, crs, rand.Reader)
+}
+
+// NewMultiWeightWTSWithRand is NewMultiWeightWTS with the signing keys, and any later randomness, sampled from rnd
+func NewMultiWeightWTSWithRand(n int, weights [][]int, crs CRS, rnd io.Reader) (WTS, error) {
+	if len(weights) == 0 {
+		return WTS{}, fmt.Errorf("%w: no weight vector", ErrWeightDims)
+	}
+	for d, dim := range weights {
+		if len(dim) != n {
+			return WTS{}, fmt.Errorf("%w: dimension %d has %d weights for %d signers", ErrWeightDims, d, len(dim), n)
+		}
+	}
+	w, err := NewWTSWithRand(n, weights[0], crs, rnd)
+	if err != nil {
+		return WTS{}, err
+	}
+	w.dims = weights[1:]
+	w.weightComms()
+	w.updateCommitteeID()
+	return w, nil
+}
+
+// Dims is the number of weight vectors of the committee
+func (w *WTS) Dims() int {
+	return 1 + len(w.dims)
+}
+
+// MultiWeightSig proves the weight of the signers in every dimension with a single
+// inner-product proof: the weight vectors are batched with the powers of the challenge,
+// o(X) = p(X) + xi.w_0(X) + xi^2.w_1(X) + ..., against the same bitvector.
+type MultiWeightSig struct {
+	Sig
+	dimWeights []int // Weights of the signers in dimensions 1, 2, ...
+}
+
+// Weights returns the weight of the signers in each dimension
+func (s *MultiWeightSig) Weights() []int {
+	return append([]int{s.ths}, s.dimWeights...)
+}
+
+// CombineMulti is combine for a MultiWeightSig over all the dimensions of the committee
+func (w *WTS) CombineMulti(signers []int, sigmas []bls.G2Jac) (MultiWeightSig, error) {
+	parts, err := w.combineParts(signers, sigmas)
+	if err != nil {
+		return MultiWeightSig{}, err
+	}
+	signers = parts.signers
+
+	weights := make([]int, len(w.dims))
+	qwTaus := make([]bls.G1Jac, len(w.dims))
+	rwTaus := make([]bls.G1Jac, len(w.dims))
+	pwTausH := make([]bls.G1Jac, len(w.dims))
+	parallelFor(len(w.dims), func(d int) {
+		for _, idx := range signers {
+			weights[d] += w.dims[d][idx]
+		}
+		qwTaus[d], rwTaus[d], pwTausH[d] = w.weightsPf(signers, w.dims[d])
+	})
+
+	cid := w.CommitteeID()
+	vals := append([]bls.G1Affine{w.pp.pComm, w.pp.wTau}, w.pp.wTaus...)
+	xi := w.getFSChal(cid, append(vals, parts.bTau, parts.aggPk), append([]int{parts.weight}, weights...)...)
+	sig := parts.sig(xi)
+
+	var qTau, rTau, pTau bls.G1Jac
+	qTau.FromAffine(&sig.pi.qTau)
+	rTau.FromAffine(&sig.pi.rTau)
+	pTau.FromAffine(&sig.pTau)
+	xiD := xi
+	for d := range w.dims {
+		xiD.Mul(&xiD, &xi)
+		xiInt := xiD.BigInt(&big.Int{})
+		qTau.AddAssign(qwTaus[d].ScalarMultiplication(&qwTaus[d], xiInt))
+		rTau.AddAssign(rwTaus[d].ScalarMultiplication(&rwTaus[d], xiInt))
+		pTau.AddAssign(pwTausH[d].ScalarMultiplication(&pwTausH[d], xiInt))
+	}
+	sig.pi.qTau.FromJacobian(&qTau)
+	sig.pi.rTau.FromJacobian(&rTau)
+	sig.pTau.FromJacobian(&pTau)
+
+	return MultiWeightSig{Sig: sig, dimWeights: weights}, nil
+}
+
+// VerifyMulti checks that sigma is a signature on msg with a weight of at least ths[d]
+// in every dimension d. Like Verify it returns the first failed check as a *VerifyError.
+func (w *WTS) VerifyMulti(msg Message, sigma MultiWeightSig, ths []int) error {
+	if len(ths) != w.Dims() || len(sigma.dimWeights) != len(w.dims) {
+		return fmt.Errorf("%w: %d thresholds and %d weights for %d dimensions", ErrWeightDims, len(ths), 1+len(sigma.dimWeights), w.Dims())
+	}
+	weights := sigma.Weights()
+	fail := func(err error) error {
+		return &VerifyError{Err: err, Claimed: weights[0], Required: ths[0]}
+	}
+
+	// 0. Checking the claimed weight of every dimension
+	for d := range weights {
+		if weights[d] < ths[d] {
+			return &VerifyError{Err: ErrInsufficientWeight, Claimed: weights[d], Required: ths[d], Dim: d}
+		}
+	}
+
+	cid := w.CommitteeID()
+	vals := append([]bls.G1Affine{w.pp.pComm, w.pp.wTau}, w.pp.wTaus...)
+	xi := w.getFSChal(cid, append(vals, sigma.bTau, sigma.aggPk), weights...)
+	wts := make([]bls.G1Affine, len(weights))
+	for d, wt := range weights {
+		wts[d].ScalarMultiplication(&w.crs.g1a, big.NewInt(int64(wt)))
+	}
+	return w.verifyChecks(cid, msg, sigma.Sig, xi, wts, fail)
+}
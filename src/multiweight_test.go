@@ -0,0 +1,95 @@
+package wts
+
+import (
+	"errors"
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiWeight(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 4
+	stake := make([]int, n)
+	orgs := make([]int, n)
+	region := make([]int, n)
+	for i := 0; i < n; i++ {
+		stake[i] = i + 1
+		orgs[i] = 1
+		region[i] = i % 3
+	}
+
+	_, err := NewMultiWeightWTS(n, [][]int{stake, orgs[1:]}, GenCRS(n))
+	assert.ErrorIs(t, err, ErrWeightDims)
+
+	w, err := NewMultiWeightWTS(n, [][]int{stake, orgs, region}, GenCRS(n))
+	assert.NoError(t, err)
+	w.preProcess()
+	assert.Equal(t, w.Dims(), 3)
+
+	signers := []int{0, 1, 2, 3, 4, 5, 6, 7, 15}
+	sigmas := make([]bls.G2Jac, len(signers))
+	ths := []int{0, 0, 0}
+	for i, idx := range signers {
+		sigmas[i] = w.psign(msg, w.signers[idx])
+		ths[0] += stake[idx]
+		ths[1] += orgs[idx]
+		ths[2] += region[idx]
+	}
+	sig, err := w.CombineMulti(signers, sigmas)
+	assert.NoError(t, err)
+	assert.Equal(t, sig.Weights(), ths)
+	assert.NoError(t, w.VerifyMulti(msg, sig, ths))
+	assert.NoError(t, w.VerifyMulti(msg, sig, []int{ths[0], n / 2, 1}))
+
+	// The proof batches all the dimensions, so it is not a plain signature on its own
+	assert.ErrorIs(t, w.Verify(msg, sig.Sig, ths[0]), ErrInnerProduct)
+
+	err = w.VerifyMulti(msg, sig, []int{ths[0], ths[1] + 1, 0})
+	assert.ErrorIs(t, err, ErrInsufficientWeight)
+	var vErr *VerifyError
+	assert.Equal(t, errors.As(err, &vErr), true)
+	assert.Equal(t, vErr.Dim, 1)
+	assert.ErrorIs(t, w.VerifyMulti(msg, sig, ths[:2]), ErrWeightDims)
+
+	// Overclaiming in any dimension breaks the inner-product proof
+	for d := 1; d < w.Dims(); d++ {
+		bad := sig
+		bad.dimWeights = append([]int(nil), sig.dimWeights...)
+		bad.dimWeights[d-1]++
+		assert.ErrorIs(t, w.VerifyMulti(msg, bad, ths), ErrInnerProduct)
+	}
+}
+
+func TestMultiWeightWithRand(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 3
+	stake := make([]int, n)
+	orgs := make([]int, n)
+	for i := 0; i < n; i++ {
+		stake[i] = i + 1
+		orgs[i] = 1
+	}
+
+	crs := GenCRS(n)
+	w1, err := NewMultiWeightWTSWithRand(n, [][]int{stake, orgs}, crs, NewSeededReader([]byte("seed")))
+	assert.NoError(t, err)
+	w2, err := NewMultiWeightWTSWithRand(n, [][]int{stake, orgs}, crs, NewSeededReader([]byte("seed")))
+	assert.NoError(t, err)
+	assert.Equal(t, w1.CommitteeID(), w2.CommitteeID())
+	w1.preProcess()
+
+	// Unsorted signers are normalized once, by combineParts
+	signers := []int{5, 0, 3}
+	sigmas := make([]bls.G2Jac, len(signers))
+	ths := []int{0, 0}
+	for i, idx := range signers {
+		sigmas[i] = w1.psign(msg, w1.signers[idx])
+		ths[0] += stake[idx]
+		ths[1] += orgs[idx]
+	}
+	sig, err := w1.CombineMulti(signers, sigmas)
+	assert.NoError(t, err)
+	assert.NoError(t, w1.VerifyMulti(msg, sig, ths))
+}
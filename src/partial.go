@@ -0,0 +1,118 @@
+package wts
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+var (
+	ErrPartialEncoding = errors.New("wts: malformed partial signature encoding")
+	ErrPartialContext  = errors.New("wts: partial signature is for another committee or message")
+	ErrPartialSig      = errors.New("wts: partial signature is invalid")
+)
+
+// PartialSignatureSize is the length of an encoded PartialSignature
+const PartialSignatureSize = 4 + 32 + 32 + bls.SizeOfG2AffineCompressed
+
+// PartialSignature is a partial signature with the context needed to gossip it:
+// the slot of the signer, the committee and the SHA-256 digest of the message
+type PartialSignature struct {
+	Slot      int
+	Committee CommitteeID
+	Digest    [32]byte
+	Sigma     bls.G2Affine
+}
+
+// PartialSign signs msg as the signer in slot
+func (w *WTS) PartialSign(msg Message, slot int) (PartialSignature, error) {
+	if slot < 0 || slot >= w.n {
+		return PartialSignature{}, fmt.Errorf("%w: %d not in [0, %d)", ErrSignerRange, slot, w.n)
+	}
+	sigma := w.psign(msg, w.signers[slot])
+	return PartialSignature{
+		Slot:      slot,
+		Committee: w.CommitteeID(),
+		Digest:    sha256.Sum256(msg),
+		Sigma:     *new(bls.G2Affine).FromJacobian(&sigma),
+	}, nil
+}
+
+// Verify checks that p is a signature on msg under the verification key vk of its signer.
+// The message is needed as the signature is on its hash to G2, the digest only binds it.
+func (p *PartialSignature) Verify(vk bls.G1Affine, msg Message) error {
+	if sha256.Sum256(msg) != p.Digest {
+		return ErrPartialContext
+	}
+	roMsg, err := hashMsg(p.Committee, msg)
+	if err != nil {
+		return err
+	}
+	_, _, g1, _ := bls.Generators()
+	g1.Neg(&g1)
+	if res, _ := bls.PairingCheck([]bls.G1Affine{vk, g1}, []bls.G2Affine{roMsg, p.Sigma}); !res {
+		return ErrPartialSig
+	}
+	return nil
+}
+
+// VerifyPartial checks that p is a partial signature on msg by a signer of the committee
+func (w *WTS) VerifyPartial(p *PartialSignature, msg Message) error {
+	if p.Slot < 0 || p.Slot >= w.n {
+		return fmt.Errorf("%w: %d not in [0, %d)", ErrSignerRange, p.Slot, w.n)
+	}
+	if p.Committee != w.CommitteeID() {
+		return ErrPartialContext
+	}
+	return p.Verify(w.signers[p.Slot].pKeyAff, msg)
+}
+
+// CombinePartials is combine for partial signatures on msg. They are checked to be for
+// this committee and message but, as for combine, assumed to be valid, see VerifyPartial.
+func (w *WTS) CombinePartials(msg Message, partials []PartialSignature) (Sig, error) {
+	cid := w.CommitteeID()
+	digest := sha256.Sum256(msg)
+	signers := make([]int, len(partials))
+	sigmas := make([]bls.G2Jac, len(partials))
+	for i, p := range partials {
+		if p.Committee != cid || p.Digest != digest {
+			return Sig{}, fmt.Errorf("%w: slot %d", ErrPartialContext, p.Slot)
+		}
+		signers[i] = p.Slot
+		sigmas[i].FromAffine(&p.Sigma)
+	}
+	return w.combine(signers, sigmas)
+}
+
+// MarshalBinary encodes the slot, committee, digest and compressed signature in
+// PartialSignatureSize bytes
+func (p *PartialSignature) MarshalBinary() ([]byte, error) {
+	if p.Slot < 0 || uint64(p.Slot) > uint64(^uint32(0)) {
+		return nil, fmt.Errorf("%w: slot %d", ErrPartialEncoding, p.Slot)
+	}
+	buf := make([]byte, 0, PartialSignatureSize)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(p.Slot))
+	buf = append(buf, p.Committee[:]...)
+	buf = append(buf, p.Digest[:]...)
+	sigma := p.Sigma.Bytes()
+	return append(buf, sigma[:]...), nil
+}
+
+// UnmarshalBinary decodes an encoding of MarshalBinary, the signature is checked to be in G2
+func (p *PartialSignature) UnmarshalBinary(data []byte) error {
+	if len(data) != PartialSignatureSize {
+		return fmt.Errorf("%w: %d bytes", ErrPartialEncoding, len(data))
+	}
+	var dec PartialSignature
+	dec.Slot = int(binary.BigEndian.Uint32(data))
+	copy(dec.Committee[:], data[4:36])
+	copy(dec.Digest[:], data[36:68])
+	if _, err := dec.Sigma.SetBytes(data[68:]); err != nil {
+		return fmt.Errorf("%w: %v", ErrPartialEncoding, err)
+	}
+	*p = dec
+	return nil
+}
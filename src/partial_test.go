@@ -0,0 +1,59 @@
+package wts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartialSignature(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 3
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+	w := NewWTS(n, weights, GenCRS(n))
+	w.preProcess()
+
+	var partials []PartialSignature
+	ths := 0
+	for _, slot := range []int{1, 4, 6} {
+		p, err := w.PartialSign(msg, slot)
+		assert.NoError(t, err)
+
+		enc, err := p.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, len(enc), PartialSignatureSize)
+		var dec PartialSignature
+		assert.NoError(t, dec.UnmarshalBinary(enc))
+		assert.Equal(t, dec, p)
+
+		assert.NoError(t, dec.Verify(w.signers[slot].pKeyAff, msg))
+		assert.NoError(t, w.VerifyPartial(&dec, msg))
+		assert.ErrorIs(t, dec.Verify(w.signers[0].pKeyAff, msg), ErrPartialSig)
+		assert.ErrorIs(t, dec.Verify(w.signers[slot].pKeyAff, []byte("other message")), ErrPartialContext)
+
+		partials = append(partials, dec)
+		ths += weights[slot]
+	}
+
+	sig, err := w.CombinePartials(msg, partials)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Verify(msg, sig, ths))
+	_, err = w.CombinePartials([]byte("other message"), partials)
+	assert.ErrorIs(t, err, ErrPartialContext)
+
+	// Another epoch is another committee
+	w.SetEpoch(1)
+	assert.ErrorIs(t, w.VerifyPartial(&partials[0], msg), ErrPartialContext)
+	w.SetEpoch(0)
+
+	_, err = w.PartialSign(msg, n)
+	assert.ErrorIs(t, err, ErrSignerRange)
+	enc, _ := partials[0].MarshalBinary()
+	var dec PartialSignature
+	assert.ErrorIs(t, dec.UnmarshalBinary(enc[1:]), ErrPartialEncoding)
+	enc[68] ^= 0x01
+	assert.ErrorIs(t, dec.UnmarshalBinary(enc), ErrPartialEncoding)
+}
@@ -0,0 +1,253 @@
+package wts
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const privateTag = "WTS-PRIVATE-V1"
+
+var ErrSignerProof = errors.New("wts: proof of the hidden signer bitvector is invalid")
+
+// PrivateSig proves that signers of weight at least Threshold signed a message without
+// revealing who they are.
+//
+// It does not use the succinct proof of Sig, whose bTau, aggPk and pTau determine the signer
+// set. For every slot i it carries a Pedersen commitment c_i = b_i.g + rho_i.h to the bit b_i
+// and a blinded key k_i = b_i.pk_i + gamma_i.g, with h = [tau]. An OR proof per slot shows
+// that (c_i, k_i) is (rho_i.h, gamma_i.g) or (g + rho_i.h, pk_i + gamma_i.g). The aggregated
+// key sum_i k_i is the key of the signers plus gamma.g, with gamma = sum_i gamma_i, and
+// verifies aggSig plus gamma.H(m). The commitments add up to sum_i w_i.c_i, a commitment to
+// the weight of the signers, and a range proof shows it is at least Threshold.
+//
+// The signature and its verification are linear in the size of the committee.
+type PrivateSig struct {
+	Threshold int
+	comms     []bls.G1Affine // c_i
+	keys      []bls.G1Affine // k_i
+	aggSig    bls.G2Jac      // Aggregated signature plus gamma.H(m)
+	bitPfs    []bitProof
+	rangePf   RangeProof
+}
+
+// OR proof that (c, k) opens to the bit 0 or 1, branch j proves knowledge of the logs of
+// c - j.g in base h and k - j.pk in base g
+type bitProof struct {
+	e  [2]fr.Element // Challenges of the two branches
+	zc [2]fr.Element // Responses for the log of c
+	zk [2]fr.Element // Responses for the log of k
+}
+
+// CombinePrivate is combine for a PrivateSig on msg proving a weight of at least ths
+func (w *WTS) CombinePrivate(msg Message, signers []int, sigmas []bls.G2Jac, ths int) (PrivateSig, error) {
+	signers, sigmas, err := w.normalizeSigners(signers, sigmas)
+	if err != nil {
+		return PrivateSig{}, err
+	}
+	weight := 0
+	bs := make([]bool, w.n)
+	for _, idx := range signers {
+		bs[idx] = true
+		weight += w.weights[idx]
+	}
+	if ths < 0 || weight < ths {
+		return PrivateSig{}, fmt.Errorf("%w: weight %d, threshold %d", ErrInsufficientWeight, weight, ths)
+	}
+
+	// rho_i, gamma_i, the two nonces of the real branch and the simulated e, zc, zk
+	rs, err := RandomElements(w.rnd, 7*w.n)
+	if err != nil {
+		return PrivateSig{}, err
+	}
+	rhos, gammas := rs[:w.n], rs[w.n:2*w.n]
+
+	sigma := PrivateSig{
+		Threshold: ths,
+		comms:     make([]bls.G1Affine, w.n),
+		keys:      make([]bls.G1Affine, w.n),
+		bitPfs:    make([]bitProof, w.n),
+	}
+	parallelFor(w.n, func(i int) {
+		sigma.comms[i].ScalarMultiplication(&w.crs.PoT[1], rhos[i].BigInt(&big.Int{}))
+		sigma.keys[i].ScalarMultiplication(&w.crs.g1a, gammas[i].BigInt(&big.Int{}))
+		if bs[i] {
+			sigma.comms[i].Add(&sigma.comms[i], &w.crs.g1a)
+			sigma.keys[i].Add(&sigma.keys[i], &w.pp.pKeys[i])
+		}
+	})
+
+	cid := w.CommitteeID()
+	roMsg, err := hashMsg(cid, msg)
+	if err != nil {
+		return PrivateSig{}, err
+	}
+	var gamma fr.Element
+	for i := range gammas {
+		gamma.Add(&gamma, &gammas[i])
+	}
+	var gammaMsg bls.G2Jac
+	gammaMsg.FromAffine(&roMsg)
+	gammaMsg.ScalarMultiplication(&gammaMsg, gamma.BigInt(&big.Int{}))
+	sigma.aggSig = gammaMsg
+	for i := range sigmas {
+		sigma.aggSig.AddAssign(&sigmas[i])
+	}
+
+	transcript := w.privateTranscript(cid, &sigma)
+	parallelFor(w.n, func(i int) {
+		nonces := rs[2*w.n+5*i : 2*w.n+5*i+5]
+		sigma.bitPfs[i] = w.proveBit(i, bs[i], sigma.comms[i], sigma.keys[i], rhos[i], gammas[i], nonces, transcript)
+	})
+
+	// The weight commitment opens to weight with sum_i w_i.rho_i
+	var rho, wF fr.Element
+	for i := 0; i < w.n; i++ {
+		wF.SetUint64(uint64(w.weights[i]))
+		wF.Mul(&wF, &rhos[i])
+		rho.Add(&rho, &wF)
+	}
+	sigma.rangePf, err = proveRange(w.crs.g1a, w.crs.PoT[1], uint64(weight-ths), rho, w.weightBits(), transcript, w.rnd)
+	if err != nil {
+		return PrivateSig{}, err
+	}
+	return sigma, nil
+}
+
+// VerifyPrivate checks that sigma is a signature on msg of weight at least ths
+func (w *WTS) VerifyPrivate(msg Message, sigma PrivateSig, ths int) error {
+	fail := func(err error) error {
+		return &VerifyError{Err: err, Claimed: sigma.Threshold, Required: ths}
+	}
+
+	// 0. Checking the claimed weight
+	if sigma.Threshold < ths {
+		return fail(ErrInsufficientWeight)
+	}
+	if len(sigma.comms) != w.n || len(sigma.keys) != w.n || len(sigma.bitPfs) != w.n {
+		return fail(ErrSignerProof)
+	}
+
+	// 1. Checking the aggregated signature against the sum of the blinded keys
+	cid := w.CommitteeID()
+	roMsg, err := hashMsg(cid, msg)
+	if err != nil {
+		return err
+	}
+	var aggPk bls.G1Jac
+	for i := range sigma.keys {
+		aggPk.AddMixed(&sigma.keys[i])
+	}
+	aggPkAff := *new(bls.G1Affine).FromJacobian(&aggPk)
+	aggSig := *new(bls.G2Affine).FromJacobian(&sigma.aggSig)
+	if res, _ := bls.PairingCheck([]bls.G1Affine{aggPkAff, w.crs.g1InvAff}, []bls.G2Affine{roMsg, aggSig}); !res {
+		return fail(ErrAggSig)
+	}
+
+	// 2. Checking that every slot commits to a bit and blinds the matching key
+	transcript := w.privateTranscript(cid, &sigma)
+	valid := make([]bool, w.n)
+	parallelFor(w.n, func(i int) {
+		valid[i] = w.verifyBit(i, sigma.comms[i], sigma.keys[i], sigma.bitPfs[i], transcript)
+	})
+	for _, v := range valid {
+		if !v {
+			return fail(ErrSignerProof)
+		}
+	}
+
+	// 3. Checking that sum_i w_i.c_i - Threshold.g commits to a value in [0, 2^k)
+	weightsF := make([]fr.Element, w.n)
+	for i := 0; i < w.n; i++ {
+		weightsF[i].SetUint64(uint64(w.weights[i]))
+	}
+	var excess, thsG bls.G1Affine
+	excess.MultiExp(sigma.comms, weightsF, ecc.MultiExpConfig{})
+	thsG.ScalarMultiplication(&w.crs.g1a, big.NewInt(int64(sigma.Threshold)))
+	excess.Sub(&excess, &thsG)
+	if !verifyRange(w.crs.g1a, w.crs.PoT[1], excess, w.weightBits(), sigma.rangePf, transcript) {
+		return fail(ErrRangeProof)
+	}
+	return nil
+}
+
+// Branch j of the OR proof of slot i: c - j.g and k - j.pk_i
+func (w *WTS) bitStatements(i int, c, k bls.G1Affine) (ycs, yks [2]bls.G1Affine) {
+	ycs[0], yks[0] = c, k
+	ycs[1].Sub(&c, &w.crs.g1a)
+	yks[1].Sub(&k, &w.pp.pKeys[i])
+	return ycs, yks
+}
+
+func (w *WTS) proveBit(i int, b bool, c, k bls.G1Affine, rho, gamma fr.Element, nonces []fr.Element, transcript []byte) bitProof {
+	h, g := w.crs.PoT[1], w.crs.g1a
+	ycs, yks := w.bitStatements(i, c, k)
+	bit := 0
+	if b {
+		bit = 1
+	}
+	sim := 1 - bit
+
+	// Committing for the real branch and simulating the other one
+	var pf bitProof
+	var acs, aks [2]bls.G1Affine
+	acs[bit].ScalarMultiplication(&h, nonces[0].BigInt(&big.Int{}))
+	aks[bit].ScalarMultiplication(&g, nonces[1].BigInt(&big.Int{}))
+	pf.e[sim], pf.zc[sim], pf.zk[sim] = nonces[2], nonces[3], nonces[4]
+	acs[sim] = schnorrCommit(h, ycs[sim], pf.e[sim], pf.zc[sim])
+	aks[sim] = schnorrCommit(g, yks[sim], pf.e[sim], pf.zk[sim])
+
+	e := bitChallenge(transcript, i, acs, aks)
+	pf.e[bit].Sub(&e, &pf.e[sim])
+	pf.zc[bit].Mul(&pf.e[bit], &rho).Add(&pf.zc[bit], &nonces[0])
+	pf.zk[bit].Mul(&pf.e[bit], &gamma).Add(&pf.zk[bit], &nonces[1])
+	return pf
+}
+
+func (w *WTS) verifyBit(i int, c, k bls.G1Affine, pf bitProof, transcript []byte) bool {
+	h, g := w.crs.PoT[1], w.crs.g1a
+	ycs, yks := w.bitStatements(i, c, k)
+	var acs, aks [2]bls.G1Affine
+	for j := 0; j < 2; j++ {
+		acs[j] = schnorrCommit(h, ycs[j], pf.e[j], pf.zc[j])
+		aks[j] = schnorrCommit(g, yks[j], pf.e[j], pf.zk[j])
+	}
+	e := bitChallenge(transcript, i, acs, aks)
+	var eSum fr.Element
+	eSum.Add(&pf.e[0], &pf.e[1])
+	return eSum.Equal(&e)
+}
+
+func bitChallenge(transcript []byte, i int, acs, aks [2]bls.G1Affine) fr.Element {
+	hFunc := sha256.New()
+	hFunc.Write(transcript)
+	hFunc.Write(binary.BigEndian.AppendUint32(nil, uint32(i)))
+	for _, p := range []bls.G1Affine{acs[0], aks[0], acs[1], aks[1]} {
+		b := p.Bytes()
+		hFunc.Write(b[:])
+	}
+	return *new(fr.Element).SetBytes(hFunc.Sum(nil))
+}
+
+// Digest of the statement every proof of a PrivateSig is bound to
+func (w *WTS) privateTranscript(cid CommitteeID, sigma *PrivateSig) []byte {
+	hFunc := sha256.New()
+	hFunc.Write([]byte(privateTag))
+	hFunc.Write(cid[:])
+	hFunc.Write(binary.BigEndian.AppendUint64(nil, uint64(sigma.Threshold)))
+	for i := range sigma.comms {
+		c, k := sigma.comms[i].Bytes(), sigma.keys[i].Bytes()
+		hFunc.Write(c[:])
+		hFunc.Write(k[:])
+	}
+	aggSig := *new(bls.G2Affine).FromJacobian(&sigma.aggSig)
+	b := aggSig.Bytes()
+	hFunc.Write(b[:])
+	return hFunc.Sum(nil)
+}
@@ -0,0 +1,77 @@
+package wts
+
+import (
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrivateSig(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 4
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+
+	w := NewWTS(n, weights, GenCRS(n))
+	w.preProcess()
+
+	signers := []int{1, 4, 9, 15}
+	sigmas := make([]bls.G2Jac, len(signers))
+	weight := 0
+	for i, idx := range signers {
+		sigmas[i] = w.psign(msg, w.signers[idx])
+		weight += weights[idx]
+	}
+
+	for _, ths := range []int{0, weight / 2, weight} {
+		sig, err := w.CombinePrivate(msg, signers, sigmas, ths)
+		assert.NoError(t, err)
+		assert.NoError(t, w.VerifyPrivate(msg, sig, ths))
+		assert.ErrorIs(t, w.VerifyPrivate(msg, sig, ths+1), ErrInsufficientWeight)
+	}
+	_, err := w.CombinePrivate(msg, signers, sigmas, weight+1)
+	assert.ErrorIs(t, err, ErrInsufficientWeight)
+
+	sig, err := w.CombinePrivate(msg, signers, sigmas, weight)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, w.VerifyPrivate([]byte("other"), sig, weight), ErrAggSig)
+
+	// Two signatures of the same signers share no element
+	again, err := w.CombinePrivate(msg, signers, sigmas, weight)
+	assert.NoError(t, err)
+	for i := 0; i < n; i++ {
+		assert.NotEqual(t, sig.comms[i], again.comms[i])
+		assert.NotEqual(t, sig.keys[i], again.keys[i])
+	}
+
+	// The threshold is bound into every proof
+	bad := sig
+	bad.Threshold = weight + 1
+	assert.ErrorIs(t, w.VerifyPrivate(msg, bad, weight+1), ErrSignerProof)
+	bad = sig
+	bad.rangePf.bits = append([]bls.G1Affine(nil), sig.rangePf.bits...)
+	bad.rangePf.bits[0] = w.crs.g1a
+	assert.ErrorIs(t, w.VerifyPrivate(msg, bad, weight), ErrRangeProof)
+
+	// Moving a signer to another slot keeps the aggregated key but breaks its bit proof
+	bad = sig
+	bad.keys = append([]bls.G1Affine(nil), sig.keys...)
+	bad.keys[1].Sub(&bad.keys[1], &w.pp.pKeys[1])
+	bad.keys[1].Add(&bad.keys[1], &w.pp.pKeys[15])
+	bad.keys[15].Sub(&bad.keys[15], &w.pp.pKeys[15])
+	bad.keys[15].Add(&bad.keys[15], &w.pp.pKeys[1])
+	assert.ErrorIs(t, w.VerifyPrivate(msg, bad, weight), ErrSignerProof)
+
+	// A commitment to 2 for a single signer
+	bad = sig
+	bad.comms = append([]bls.G1Affine(nil), sig.comms...)
+	bad.comms[4].Add(&bad.comms[4], &w.crs.g1a)
+	assert.ErrorIs(t, w.VerifyPrivate(msg, bad, weight), ErrSignerProof)
+
+	bad = sig
+	bad.bitPfs = sig.bitPfs[1:]
+	assert.ErrorIs(t, w.VerifyPrivate(msg, bad, weight), ErrSignerProof)
+}
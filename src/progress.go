@@ -0,0 +1,48 @@
+package wts
+
+import (
+	"context"
+	"sync"
+)
+
+// ProgressFunc is called after every batch of a long-running phase ("crs", "keygen" or
+// "preprocess") with the number of batches done so far out of total.
+// It may be called from several goroutines, but never concurrently.
+type ProgressFunc func(phase string, done, total int)
+
+// Phases reported to a ProgressFunc
+const (
+	PhaseCRS        = "crs"
+	PhaseKeyGen     = "keygen"
+	PhasePreProcess = "preprocess"
+)
+
+// Counts the batches of a phase and checks for cancellation between them
+type tracker struct {
+	ctx      context.Context
+	progress ProgressFunc
+	phase    string
+	total    int
+
+	mu   sync.Mutex
+	done int
+}
+
+func newTracker(ctx context.Context, progress ProgressFunc, phase string, total int) *tracker {
+	return &tracker{ctx: ctx, progress: progress, phase: phase, total: total}
+}
+
+// step marks one batch as done and returns the context error, if any.
+// A nil tracker does nothing.
+func (t *tracker) step() error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	t.done++
+	if t.progress != nil {
+		t.progress(t.phase, t.done, t.total)
+	}
+	t.mu.Unlock()
+	return t.ctx.Err()
+}
@@ -0,0 +1,54 @@
+package wts
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress(t *testing.T) {
+	n := 1 << 4
+	weights := make([]int, n)
+
+	last := make(map[string][2]int)
+	progress := func(phase string, done, total int) {
+		assert.Equal(t, last[phase][0]+1, done)
+		last[phase] = [2]int{done, total}
+	}
+
+	ctx := context.Background()
+	crs, err := GenCRSContext(ctx, n, rand.Reader, progress)
+	assert.NoError(t, err)
+	w, err := NewWTSContext(ctx, n, weights, crs, rand.Reader, progress)
+	assert.NoError(t, err)
+	assert.NoError(t, w.PreProcessContext(ctx, progress))
+
+	for _, phase := range []string{PhaseCRS, PhaseKeyGen, PhasePreProcess} {
+		assert.Equal(t, last[phase][0], last[phase][1], phase)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	n := 1 << 4
+	weights := make([]int, n)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := func(string, int, int) { cancel() }
+
+	_, err := GenCRSContext(ctx, n, rand.Reader, cancelled)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	crs := GenCRS(n)
+	ctx, cancel = context.WithCancel(context.Background())
+	_, err = NewWTSContext(ctx, n, weights, crs, rand.Reader, cancelled)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	w, err := NewWTSWithRand(n, weights, crs, rand.Reader)
+	assert.NoError(t, err)
+	ctx, cancel = context.WithCancel(context.Background())
+	assert.ErrorIs(t, w.PreProcessContext(ctx, cancelled), context.Canceled)
+	assert.ErrorIs(t, w.PreProcessContext(ctx, nil), context.Canceled)
+	cancel()
+}
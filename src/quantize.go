@@ -0,0 +1,87 @@
+package wts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+var ErrStakes = errors.New("wts: invalid stake distribution")
+
+// Quantization maps raw stakes to the small integer weights of a committee
+type Quantization struct {
+	Weights []int
+	Total   int // Sum of the weights
+	// The share of the weight of any coalition is within Bound of its share of the stake
+	Bound *big.Rat
+}
+
+// QuantizeStakes scales the stakes to a total weight of about total and rounds them.
+//
+// With e_i = w_i - total.s_i/S the rounding errors, a coalition C holding a share a of the
+// stake has w(C)/W - a = ((1-a).e(C) - a.e(not C))/W, which is at most sum_i |e_i|/W = Bound.
+func QuantizeStakes(stakes []*big.Int, total int) (Quantization, error) {
+	if len(stakes) == 0 || total <= 0 {
+		return Quantization{}, fmt.Errorf("%w: %d stakes, total weight %d", ErrStakes, len(stakes), total)
+	}
+	sum := new(big.Int)
+	for i, s := range stakes {
+		if s.Sign() < 0 {
+			return Quantization{}, fmt.Errorf("%w: stake %d is negative", ErrStakes, i)
+		}
+		sum.Add(sum, s)
+	}
+	if sum.Sign() == 0 {
+		return Quantization{}, fmt.Errorf("%w: all stakes are zero", ErrStakes)
+	}
+
+	q := Quantization{Weights: make([]int, len(stakes)), Bound: new(big.Rat)}
+	tot := big.NewInt(int64(total))
+	twoSum := new(big.Int).Lsh(sum, 1)
+	var num big.Int
+	var err big.Rat
+	for i, s := range stakes {
+		// w_i = round(total.s_i/S) = floor((2.total.s_i + S)/2S)
+		num.Mul(s, tot).Lsh(&num, 1).Add(&num, sum).Quo(&num, twoSum)
+		q.Weights[i] = int(num.Int64())
+		q.Total += q.Weights[i]
+
+		err.SetFrac(new(big.Int).Mul(s, tot), sum)
+		err.Sub(new(big.Rat).SetInt64(int64(q.Weights[i])), &err)
+		q.Bound.Add(q.Bound, err.Abs(&err))
+	}
+	if q.Total == 0 {
+		return Quantization{}, fmt.Errorf("%w: every weight rounds to zero for total weight %d", ErrStakes, total)
+	}
+	q.Bound.Quo(q.Bound, new(big.Rat).SetInt64(int64(q.Total)))
+	return q, nil
+}
+
+// QuantizeWithBound quantizes the stakes with the smallest power of two total weight,
+// starting from the number of stakes, whose Bound is at most eps
+func QuantizeWithBound(stakes []*big.Int, eps *big.Rat) (Quantization, error) {
+	if eps.Sign() <= 0 {
+		return Quantization{}, fmt.Errorf("%w: bound %v is not positive", ErrStakes, eps)
+	}
+	for total := len(stakes); total > 0 && total <= math.MaxInt32; total *= 2 {
+		q, err := QuantizeStakes(stakes, total)
+		if err != nil || q.Bound.Cmp(eps) <= 0 {
+			return q, err
+		}
+	}
+	return Quantization{}, fmt.Errorf("%w: no total weight up to 2^31 reaches bound %v", ErrStakes, eps)
+}
+
+// Threshold is the smallest weight such that signers of at least that weight hold
+// at least frac of the stake, i.e. ceil((frac + Bound).Total). It exceeds Total when
+// Bound is too large for frac.
+func (q *Quantization) Threshold(frac *big.Rat) int {
+	t := new(big.Rat).Add(frac, q.Bound)
+	t.Mul(t, new(big.Rat).SetInt64(int64(q.Total)))
+	ths := new(big.Int).Quo(t.Num(), t.Denom())
+	if !t.IsInt() {
+		ths.Add(ths, big.NewInt(1))
+	}
+	return int(ths.Int64())
+}
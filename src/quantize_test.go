@@ -0,0 +1,64 @@
+package wts
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantizeStakes(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	n := 64
+	stakes := make([]*big.Int, n)
+	sum := new(big.Int)
+	for i := range stakes {
+		// Stakes of up to 2^80 with a heavy tail
+		stakes[i] = new(big.Int).Lsh(big.NewInt(rnd.Int63n(1<<20)+1), uint(rnd.Intn(60)))
+		sum.Add(sum, stakes[i])
+	}
+
+	eps := big.NewRat(1, 100)
+	q, err := QuantizeWithBound(stakes, eps)
+	assert.NoError(t, err)
+	assert.Equal(t, q.Bound.Cmp(eps) <= 0, true)
+	assert.Equal(t, len(q.Weights), n)
+
+	// The bound holds for random coalitions
+	for trial := 0; trial < 200; trial++ {
+		stake, weight := new(big.Int), 0
+		for i := 0; i < n; i++ {
+			if rnd.Intn(2) == 0 {
+				stake.Add(stake, stakes[i])
+				weight += q.Weights[i]
+			}
+		}
+		diff := new(big.Rat).SetFrac64(int64(weight), int64(q.Total))
+		diff.Sub(diff, new(big.Rat).SetFrac(stake, sum))
+		assert.Equal(t, diff.Abs(diff).Cmp(q.Bound) <= 0, true)
+	}
+
+	// Signers meeting the threshold hold at least 2/3 of the stake
+	frac := big.NewRat(2, 3)
+	ths := q.Threshold(frac)
+	order, _ := SelectFewest(&WTS{weights: q.Weights}, GetRange(0, n))
+	weight, stake := 0, new(big.Int)
+	for _, idx := range order {
+		if weight >= ths {
+			break
+		}
+		weight += q.Weights[idx]
+		stake.Add(stake, stakes[idx])
+	}
+	assert.Equal(t, new(big.Rat).SetFrac(stake, sum).Cmp(frac) >= 0, true)
+
+	_, err = QuantizeStakes(nil, 10)
+	assert.ErrorIs(t, err, ErrStakes)
+	_, err = QuantizeStakes([]*big.Int{big.NewInt(1), big.NewInt(-1)}, 10)
+	assert.ErrorIs(t, err, ErrStakes)
+	_, err = QuantizeStakes([]*big.Int{big.NewInt(0)}, 10)
+	assert.ErrorIs(t, err, ErrStakes)
+	_, err = QuantizeWithBound(stakes, new(big.Rat))
+	assert.ErrorIs(t, err, ErrStakes)
+}
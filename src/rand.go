@@ -0,0 +1,44 @@
+package wts
+
+import (
+	"crypto/sha256"
+	"io"
+
+	"wts/internal/frand"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"golang.org/x/crypto/chacha20"
+)
+
+// RandomElement samples a field element from rnd with negligible bias
+func RandomElement(rnd io.Reader) (fr.Element, error) {
+	return frand.Element(rnd)
+}
+
+// RandomElements fills a fresh slice of n field elements from rnd
+func RandomElements(rnd io.Reader, n int) ([]fr.Element, error) {
+	return frand.Elements(rnd, n)
+}
+
+type seededReader struct {
+	cipher *chacha20.Cipher
+}
+
+// NewSeededReader returns a deterministic random stream (ChaCha20 keyed by SHA-256 of seed).
+// It is meant for reproducible tests and benchmarks, production code should use crypto/rand.
+func NewSeededReader(seed []byte) io.Reader {
+	key := sha256.Sum256(seed)
+	cipher, err := chacha20.NewUnauthenticatedCipher(key[:], make([]byte, chacha20.NonceSize))
+	if err != nil {
+		panic(err) // key and nonce sizes are fixed
+	}
+	return &seededReader{cipher: cipher}
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	r.cipher.XORKeyStream(p, p)
+	return len(p), nil
+}
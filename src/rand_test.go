@@ -0,0 +1,97 @@
+package wts
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+func g1Bytes(out *bytes.Buffer, ps ...bls.G1Affine) {
+	for i := range ps {
+		b := ps[i].Bytes()
+		out.Write(b[:])
+	}
+}
+
+func g2Bytes(out *bytes.Buffer, ps ...bls.G2Affine) {
+	for i := range ps {
+		b := ps[i].Bytes()
+		out.Write(b[:])
+	}
+}
+
+// Serialises every public group element of the CRS
+func crsBytes(crs CRS) []byte {
+	var out bytes.Buffer
+	g1Bytes(&out, crs.g1Ba, crs.h1a, crs.gAlpha)
+	g1Bytes(&out, crs.PoT...)
+	g1Bytes(&out, crs.PoTH...)
+	g1Bytes(&out, crs.lagHTaus...)
+	g1Bytes(&out, crs.lagHTausH...)
+	g1Bytes(&out, crs.lagLTaus...)
+	g2Bytes(&out, crs.g2Ba, crs.h2a, crs.hTauHAff, crs.g2Tau, crs.vHTau)
+	g2Bytes(&out, crs.lag2HTaus...)
+	return out.Bytes()
+}
+
+func keysBytes(w WTS) []byte {
+	var out bytes.Buffer
+	for _, p := range w.signers {
+		sk := p.sKey.Bytes()
+		out.Write(sk[:])
+	}
+	g1Bytes(&out, w.pp.pComm)
+	g1Bytes(&out, w.pp.pKeys...)
+	g1Bytes(&out, w.pp.pKeysB...)
+	g1Bytes(&out, w.pp.hTaus...)
+	g1Bytes(&out, w.pp.aTaus...)
+	for _, lTaus := range w.pp.lTaus {
+		g1Bytes(&out, lTaus...)
+	}
+	return out.Bytes()
+}
+
+func TestSeededReader(t *testing.T) {
+	a := make([]byte, 100)
+	b := make([]byte, 100)
+	io.ReadFull(NewSeededReader([]byte("seed")), a)
+	io.ReadFull(NewSeededReader([]byte("seed")), b)
+	assert.Equal(t, a, b)
+
+	io.ReadFull(NewSeededReader([]byte("other seed")), b)
+	assert.NotEqual(t, a, b)
+}
+
+func TestSeededSetupReproducible(t *testing.T) {
+	n := 1 << 4
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i
+	}
+
+	setup := func(seed string) (CRS, WTS) {
+		rnd := NewSeededReader([]byte(seed))
+		crs, err := GenCRSWithRand(n, rnd)
+		assert.NoError(t, err)
+		w, err := NewWTSWithRand(n, weights, crs, rnd)
+		assert.NoError(t, err)
+		return crs, w
+	}
+
+	crs1, w1 := setup("reproducible")
+	crs2, w2 := setup("reproducible")
+	assert.Equal(t, crsBytes(crs1), crsBytes(crs2))
+	assert.Equal(t, keysBytes(w1), keysBytes(w2))
+
+	crs3, w3 := setup("different")
+	assert.NotEqual(t, crsBytes(crs1), crsBytes(crs3))
+	assert.NotEqual(t, keysBytes(w1), keysBytes(w3))
+}
+
+func TestRandomElementError(t *testing.T) {
+	_, err := GenCRSWithRand(1<<2, bytes.NewReader(make([]byte, 10)))
+	assert.Error(t, err)
+}
@@ -0,0 +1,135 @@
+package wts
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+var errRangeValue = errors.New("wts: value does not fit in the range proof")
+
+// RangeProof shows that a Pedersen commitment v.g + rho.h opens to some v in [0, 2^k).
+// It commits to every bit of v and proves with a CDS OR proof that each bit commitment
+// opens to 0 or 1. The bit commitments add up, with powers of two, to the commitment.
+type RangeProof struct {
+	bits   []bls.G1Affine // v_j.g + rho_j.h
+	e0, e1 []fr.Element   // Challenges of the two branches
+	z0, z1 []fr.Element   // Responses of the two branches
+}
+
+func proveRange(g, h bls.G1Affine, v uint64, rho fr.Element, k int, transcript []byte, rnd io.Reader) (RangeProof, error) {
+	if k < 64 && v>>k != 0 {
+		return RangeProof{}, errRangeValue
+	}
+	rhos, err := RandomElements(rnd, k)
+	if err != nil {
+		return RangeProof{}, err
+	}
+
+	// The last blinding makes sum_j 2^j.rho_j = rho
+	var acc, pow, two fr.Element
+	pow.SetOne()
+	two.SetUint64(2)
+	for j := 0; j < k-1; j++ {
+		var t fr.Element
+		t.Mul(&rhos[j], &pow)
+		acc.Add(&acc, &t)
+		pow.Mul(&pow, &two)
+	}
+	pow.Inverse(&pow)
+	rhos[k-1].Sub(&rho, &acc).Mul(&rhos[k-1], &pow)
+
+	pf := RangeProof{
+		bits: make([]bls.G1Affine, k),
+		e0:   make([]fr.Element, k),
+		e1:   make([]fr.Element, k),
+		z0:   make([]fr.Element, k),
+		z1:   make([]fr.Element, k),
+	}
+	for j := 0; j < k; j++ {
+		bit := (v >> j) & 1
+		pf.bits[j].ScalarMultiplication(&h, rhos[j].BigInt(&big.Int{}))
+		if bit == 1 {
+			pf.bits[j].Add(&pf.bits[j], &g)
+		}
+		ys := orStatements(g, pf.bits[j])
+
+		// Simulating the false branch and committing for the real one
+		sim, err := RandomElements(rnd, 3)
+		if err != nil {
+			return RangeProof{}, err
+		}
+		r, eSim, zSim := sim[0], sim[1], sim[2]
+		var as [2]bls.G1Affine
+		as[bit].ScalarMultiplication(&h, r.BigInt(&big.Int{}))
+		as[1-bit] = schnorrCommit(h, ys[1-bit], eSim, zSim)
+
+		e := orChallenge(transcript, j, pf.bits[j], as)
+		var eReal, zReal fr.Element
+		eReal.Sub(&e, &eSim)
+		zReal.Mul(&eReal, &rhos[j]).Add(&zReal, &r)
+		if bit == 0 {
+			pf.e0[j], pf.z0[j], pf.e1[j], pf.z1[j] = eReal, zReal, eSim, zSim
+		} else {
+			pf.e0[j], pf.z0[j], pf.e1[j], pf.z1[j] = eSim, zSim, eReal, zReal
+		}
+	}
+	return pf, nil
+}
+
+func verifyRange(g, h, comm bls.G1Affine, k int, pf RangeProof, transcript []byte) bool {
+	if len(pf.bits) != k || len(pf.e0) != k || len(pf.e1) != k || len(pf.z0) != k || len(pf.z1) != k {
+		return false
+	}
+
+	var sum bls.G1Jac
+	for j := k - 1; j >= 0; j-- {
+		sum.DoubleAssign()
+		sum.AddMixed(&pf.bits[j])
+
+		ys := orStatements(g, pf.bits[j])
+		as := [2]bls.G1Affine{
+			schnorrCommit(h, ys[0], pf.e0[j], pf.z0[j]),
+			schnorrCommit(h, ys[1], pf.e1[j], pf.z1[j]),
+		}
+		e := orChallenge(transcript, j, pf.bits[j], as)
+		var eSum fr.Element
+		eSum.Add(&pf.e0[j], &pf.e1[j])
+		if !eSum.Equal(&e) {
+			return false
+		}
+	}
+	sumAff := *new(bls.G1Affine).FromJacobian(&sum)
+	return sumAff.Equal(&comm)
+}
+
+// The bit commitment c is rho.h when the bit is 0 and g + rho.h when it is 1
+func orStatements(g, c bls.G1Affine) [2]bls.G1Affine {
+	var y1 bls.G1Affine
+	y1.Sub(&c, &g)
+	return [2]bls.G1Affine{c, y1}
+}
+
+// z.h - e.y, the commitment of a Schnorr proof of knowledge of log_h(y)
+func schnorrCommit(h, y bls.G1Affine, e, z fr.Element) bls.G1Affine {
+	var a, ey bls.G1Affine
+	a.ScalarMultiplication(&h, z.BigInt(&big.Int{}))
+	ey.ScalarMultiplication(&y, e.BigInt(&big.Int{}))
+	return *a.Sub(&a, &ey)
+}
+
+func orChallenge(transcript []byte, j int, c bls.G1Affine, as [2]bls.G1Affine) fr.Element {
+	hFunc := sha256.New()
+	hFunc.Write(transcript)
+	hFunc.Write(binary.BigEndian.AppendUint32(nil, uint32(j)))
+	for _, p := range []bls.G1Affine{c, as[0], as[1]} {
+		b := p.Bytes()
+		hFunc.Write(b[:])
+	}
+	return *new(fr.Element).SetBytes(hFunc.Sum(nil))
+}
@@ -0,0 +1,217 @@
+package wts
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+const registryTag = "WTS-REGISTRY-V1"
+
+var (
+	ErrRegistry      = errors.New("wts: invalid signer registry")
+	ErrUnknownSigner = errors.New("wts: signer is not in the registry")
+)
+
+// Registry maps stable signer ids to the slots of a committee, so that signers are not
+// identified by their position alone. Slots without a signer have the empty id.
+type Registry struct {
+	ids   []string // Id of every slot
+	slots map[string]int
+}
+
+// KeyID is the id of a signer without one: the hex of the SHA-256 of its compressed public key
+func KeyID(pk bls.G1Affine) string {
+	b := pk.Bytes()
+	h := sha256.Sum256(b[:])
+	return hex.EncodeToString(h[:])
+}
+
+// NewRegistry assigns the ids to the first slots of a committee of size n in sorted order,
+// so every node that knows the same ids gets the same mapping
+func NewRegistry(ids []string, n int) (*Registry, error) {
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	return newRegistry(sorted, n)
+}
+
+// RegistryFromSnapshot keeps the slots of the snapshot, validators without an id get their KeyID
+func RegistryFromSnapshot(s *Snapshot) (*Registry, error) {
+	ids := make([]string, len(s.Validators))
+	for i, v := range s.Validators {
+		ids[i] = v.ID
+		if ids[i] == "" {
+			ids[i] = KeyID(v.PubKey)
+		}
+	}
+	return newRegistry(ids, s.Slots())
+}
+
+func newRegistry(ids []string, n int) (*Registry, error) {
+	if len(ids) > n {
+		return nil, fmt.Errorf("%w: %d ids for %d slots", ErrRegistry, len(ids), n)
+	}
+	r := &Registry{ids: make([]string, n), slots: make(map[string]int, len(ids))}
+	for slot, id := range ids {
+		if id == "" || len(id) > 0xffff {
+			return nil, fmt.Errorf("%w: id of slot %d is empty or too long", ErrRegistry, slot)
+		}
+		if prev, ok := r.slots[id]; ok {
+			return nil, fmt.Errorf("%w: %q is in slots %d and %d", ErrRegistry, id, prev, slot)
+		}
+		r.ids[slot] = id
+		r.slots[id] = slot
+	}
+	return r, nil
+}
+
+// Size is the number of slots
+func (r *Registry) Size() int {
+	return len(r.ids)
+}
+
+// Slot returns the slot of a signer
+func (r *Registry) Slot(id string) (int, error) {
+	slot, ok := r.slots[id]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownSigner, id)
+	}
+	return slot, nil
+}
+
+// Slots returns the slots of the signers
+func (r *Registry) Slots(ids []string) ([]int, error) {
+	slots := make([]int, len(ids))
+	for i, id := range ids {
+		var err error
+		if slots[i], err = r.Slot(id); err != nil {
+			return nil, err
+		}
+	}
+	return slots, nil
+}
+
+// IDs returns the ids of the signers in the slots
+func (r *Registry) IDs(slots []int) ([]string, error) {
+	ids := make([]string, len(slots))
+	for i, slot := range slots {
+		if slot < 0 || slot >= len(r.ids) || r.ids[slot] == "" {
+			return nil, fmt.Errorf("%w: slot %d", ErrUnknownSigner, slot)
+		}
+		ids[i] = r.ids[slot]
+	}
+	return ids, nil
+}
+
+// MarshalBinary encodes the number of slots followed by the length prefixed id of every slot
+func (r *Registry) MarshalBinary() ([]byte, error) {
+	buf := binary.BigEndian.AppendUint32(nil, uint32(len(r.ids)))
+	for _, id := range r.ids {
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(id)))
+		buf = append(buf, id...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a registry encoded by MarshalBinary
+func (r *Registry) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return fmt.Errorf("%w: truncated encoding", ErrRegistry)
+	}
+	n := int(binary.BigEndian.Uint32(data))
+	data = data[4:]
+	if n > len(data)/2 {
+		return fmt.Errorf("%w: truncated encoding", ErrRegistry)
+	}
+	dec := Registry{ids: make([]string, n), slots: make(map[string]int)}
+	for slot := 0; slot < n; slot++ {
+		if len(data) < 2 || len(data) < 2+int(binary.BigEndian.Uint16(data)) {
+			return fmt.Errorf("%w: truncated encoding", ErrRegistry)
+		}
+		l := int(binary.BigEndian.Uint16(data))
+		id := string(data[2 : 2+l])
+		data = data[2+l:]
+		if id == "" {
+			continue
+		}
+		if prev, ok := dec.slots[id]; ok {
+			return fmt.Errorf("%w: %q is in slots %d and %d", ErrRegistry, id, prev, slot)
+		}
+		dec.ids[slot] = id
+		dec.slots[id] = slot
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrRegistry, len(data))
+	}
+	*r = dec
+	return nil
+}
+
+// Digest is the SHA-256 of the encoding, nodes agree on the mapping when their digests match
+func (r *Registry) Digest() [32]byte {
+	enc, _ := r.MarshalBinary()
+	hFunc := sha256.New()
+	hFunc.Write([]byte(registryTag))
+	hFunc.Write(enc)
+	var d [32]byte
+	copy(d[:], hFunc.Sum(nil))
+	return d
+}
+
+// SetRegistry attaches the registry to the committee. Its digest becomes part of the
+// CommitteeID, so a signature only verifies for nodes that agree on the mapping.
+// The digest is taken once here, r must not be changed afterwards.
+func (w *WTS) SetRegistry(r *Registry) error {
+	if r.Size() != w.n {
+		return fmt.Errorf("%w: %d slots for %d signers", ErrRegistry, r.Size(), w.n)
+	}
+	d := r.Digest()
+	w.registry = r
+	w.registryDigest = &d
+	w.updateCommitteeID()
+	return nil
+}
+
+// Registry returns the registry of the committee, or nil
+func (w *WTS) Registry() *Registry {
+	return w.registry
+}
+
+// CombineByID is combine with the signers given by their registry ids
+func (w *WTS) CombineByID(ids []string, sigmas []bls.G2Jac) (Sig, error) {
+	signers, err := w.registrySlots(ids)
+	if err != nil {
+		return Sig{}, err
+	}
+	return w.combine(signers, sigmas)
+}
+
+// CombineAccountableByID is CombineAccountable with the signers given by their registry ids
+func (w *WTS) CombineAccountableByID(ids []string, sigmas []bls.G2Jac) (AccountableSig, error) {
+	signers, err := w.registrySlots(ids)
+	if err != nil {
+		return AccountableSig{}, err
+	}
+	return w.CombineAccountable(signers, sigmas)
+}
+
+// SignerIDs returns the registry ids of the signers in the bitmap of sigma, which should
+// have been checked with VerifyAccountable
+func (w *WTS) SignerIDs(sigma *AccountableSig) ([]string, error) {
+	if w.registry == nil {
+		return nil, fmt.Errorf("%w: committee has no registry", ErrRegistry)
+	}
+	return w.registry.IDs(sigma.Signers())
+}
+
+func (w *WTS) registrySlots(ids []string) ([]int, error) {
+	if w.registry == nil {
+		return nil, fmt.Errorf("%w: committee has no registry", ErrRegistry)
+	}
+	return w.registry.Slots(ids)
+}
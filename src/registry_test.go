@@ -0,0 +1,70 @@
+package wts
+
+import (
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 3
+	weights := []int{1, 2, 3, 4, 5, 6, 0, 0}
+	ids := []string{"frank", "alice", "erin", "bob", "dave", "carol"}
+
+	r, err := NewRegistry(ids, n)
+	assert.NoError(t, err)
+	// Shuffled input gives the same mapping
+	r2, err := NewRegistry([]string{"carol", "bob", "frank", "alice", "dave", "erin"}, n)
+	assert.NoError(t, err)
+	assert.Equal(t, r.Digest(), r2.Digest())
+	slot, err := r.Slot("carol")
+	assert.NoError(t, err)
+	assert.Equal(t, slot, 2)
+
+	enc, err := r.MarshalBinary()
+	assert.NoError(t, err)
+	var dec Registry
+	assert.NoError(t, dec.UnmarshalBinary(enc))
+	assert.Equal(t, &dec, r)
+	assert.ErrorIs(t, dec.UnmarshalBinary(enc[:len(enc)-1]), ErrRegistry)
+	assert.ErrorIs(t, dec.UnmarshalBinary(append(enc, 0)), ErrRegistry)
+
+	_, err = NewRegistry([]string{"alice", "alice"}, n)
+	assert.ErrorIs(t, err, ErrRegistry)
+	_, err = NewRegistry(ids, 4)
+	assert.ErrorIs(t, err, ErrRegistry)
+
+	w := NewWTS(n, weights, GenCRS(n))
+	w.preProcess()
+	cid := w.CommitteeID()
+	assert.ErrorIs(t, w.SetRegistry(&Registry{ids: make([]string, 4)}), ErrRegistry)
+	assert.NoError(t, w.SetRegistry(r))
+	assert.NotEqual(t, w.CommitteeID(), cid)
+
+	signers := []string{"erin", "alice", "dave"}
+	slots, err := r.Slots(signers)
+	assert.NoError(t, err)
+	sigmas := make([]bls.G2Jac, len(signers))
+	ths := 0
+	for i, slot := range slots {
+		sigmas[i] = w.psign(msg, w.signers[slot])
+		ths += weights[slot]
+	}
+	sig, err := w.CombineByID(signers, sigmas)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Verify(msg, sig, ths))
+
+	aSig, err := w.CombineAccountableByID(signers, sigmas)
+	assert.NoError(t, err)
+	assert.NoError(t, w.VerifyAccountable(msg, aSig, ths))
+	got, err := w.SignerIDs(&aSig)
+	assert.NoError(t, err)
+	assert.Equal(t, got, []string{"alice", "dave", "erin"})
+
+	_, err = w.CombineByID([]string{"mallory"}, sigmas[:1])
+	assert.ErrorIs(t, err, ErrUnknownSigner)
+	_, err = r.IDs([]int{7})
+	assert.ErrorIs(t, err, ErrUnknownSigner)
+}
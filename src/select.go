@@ -0,0 +1,112 @@
+package wts
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrStrategy is returned when a SelectStrategy orders a signer that is not available, or twice
+var ErrStrategy = errors.New("wts: selection strategy returned an unavailable or repeated signer")
+
+// SelectStrategy orders the available signers, SelectSigners takes them in that order
+// until the threshold is met. available is deduplicated and in range, and the order may
+// only contain signers of available, each at most once.
+type SelectStrategy func(w *WTS, available []int) ([]int, error)
+
+// SelectFewest takes the heaviest signers first, which gives the fewest signers
+func SelectFewest(w *WTS, available []int) ([]int, error) {
+	order := append([]int(nil), available...)
+	sort.SliceStable(order, func(i, j int) bool {
+		return w.weights[order[i]] > w.weights[order[j]]
+	})
+	return order, nil
+}
+
+// SelectEarliest takes the signers in the order of available, e.g. their arrival order
+func SelectEarliest(w *WTS, available []int) ([]int, error) {
+	return available, nil
+}
+
+// SelectDiverse takes signers from each group in turn, heaviest first within a group,
+// where groups[i] is the group of signer i, e.g. its organization or region
+func SelectDiverse(groups []int) SelectStrategy {
+	return func(w *WTS, available []int) ([]int, error) {
+		if len(groups) != w.n {
+			return nil, fmt.Errorf("wts: %d groups for %d signers", len(groups), w.n)
+		}
+		byWeight, _ := SelectFewest(w, available)
+
+		// Groups in the order they first arrive
+		var buckets [][]int
+		bucketOf := make(map[int]int)
+		for _, idx := range available {
+			if _, ok := bucketOf[groups[idx]]; !ok {
+				bucketOf[groups[idx]] = len(buckets)
+				buckets = append(buckets, nil)
+			}
+		}
+		for _, idx := range byWeight {
+			b := bucketOf[groups[idx]]
+			buckets[b] = append(buckets[b], idx)
+		}
+
+		order := make([]int, 0, len(available))
+		for k := 0; len(order) < len(available); k++ {
+			for _, bucket := range buckets {
+				if k < len(bucket) {
+					order = append(order, bucket[k])
+				}
+			}
+		}
+		return order, nil
+	}
+}
+
+// SelectSigners picks signers among available whose weight is at least ths, in the order
+// given by strategy. Repeated signers are counted once. The returned signers are sorted
+// and can be passed to combine with their partial signatures.
+func (w *WTS) SelectSigners(available []int, ths int, strategy SelectStrategy) ([]int, error) {
+	seen := make(map[int]bool, len(available))
+	var avail []int
+	total := 0
+	for _, idx := range available {
+		if idx < 0 || idx >= w.n {
+			return nil, fmt.Errorf("%w: %d not in [0, %d)", ErrSignerRange, idx, w.n)
+		}
+		if !seen[idx] {
+			seen[idx] = true
+			avail = append(avail, idx)
+			total += w.weights[idx]
+		}
+	}
+	if len(avail) == 0 {
+		return nil, ErrNoSigners
+	}
+	if total < ths {
+		return nil, fmt.Errorf("%w: available weight %d, threshold %d", ErrInsufficientWeight, total, ths)
+	}
+
+	order, err := strategy(w, avail)
+	if err != nil {
+		return nil, err
+	}
+	var selected []int
+	weight := 0
+	for _, idx := range order {
+		if len(selected) > 0 && weight >= ths {
+			break
+		}
+		if !seen[idx] {
+			return nil, fmt.Errorf("%w: %d", ErrStrategy, idx)
+		}
+		seen[idx] = false
+		selected = append(selected, idx)
+		weight += w.weights[idx]
+	}
+	if weight < ths {
+		return nil, fmt.Errorf("%w: selected weight %d, threshold %d", ErrInsufficientWeight, weight, ths)
+	}
+	sort.Ints(selected)
+	return selected, nil
+}
@@ -0,0 +1,60 @@
+package wts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectSigners(t *testing.T) {
+	n := 1 << 3
+	weights := []int{5, 1, 1, 3, 8, 2, 2, 4}
+	w := NewWTS(n, weights, GenCRS(n))
+
+	available := []int{1, 2, 5, 6, 3, 4, 7, 2}
+	sel, err := w.SelectSigners(available, 10, SelectFewest)
+	assert.NoError(t, err)
+	assert.Equal(t, sel, []int{4, 7})
+
+	sel, err = w.SelectSigners(available, 10, SelectEarliest)
+	assert.NoError(t, err)
+	assert.Equal(t, sel, []int{1, 2, 3, 4, 5, 6})
+
+	// Signers 3, 4 and 7 share a group
+	groups := []int{0, 1, 1, 2, 2, 3, 3, 2}
+	sel, err = w.SelectSigners(available, 10, SelectDiverse(groups))
+	assert.NoError(t, err)
+	assert.Equal(t, sel, []int{1, 4, 5})
+	_, err = w.SelectSigners(available, 10, SelectDiverse(groups[1:]))
+	assert.Error(t, err)
+
+	for _, strategy := range []SelectStrategy{SelectFewest, SelectEarliest, SelectDiverse(groups)} {
+		for ths := 0; ths <= 21; ths++ {
+			sel, err := w.SelectSigners(available, ths, strategy)
+			assert.NoError(t, err)
+			weight := 0
+			for _, idx := range sel {
+				weight += weights[idx]
+			}
+			assert.Equal(t, weight >= ths, true)
+		}
+	}
+
+	_, err = w.SelectSigners(available, 22, SelectFewest)
+	assert.ErrorIs(t, err, ErrInsufficientWeight)
+	_, err = w.SelectSigners(nil, 0, SelectFewest)
+	assert.ErrorIs(t, err, ErrNoSigners)
+	_, err = w.SelectSigners([]int{n}, 0, SelectFewest)
+	assert.ErrorIs(t, err, ErrSignerRange)
+
+	// Strategies are not trusted to stay within available
+	outside := func(w *WTS, available []int) ([]int, error) { return []int{n + 1}, nil }
+	_, err = w.SelectSigners(available, 10, outside)
+	assert.ErrorIs(t, err, ErrStrategy)
+	repeated := func(w *WTS, available []int) ([]int, error) { return []int{4, 4, 7}, nil }
+	_, err = w.SelectSigners(available, 10, repeated)
+	assert.ErrorIs(t, err, ErrStrategy)
+	short := func(w *WTS, available []int) ([]int, error) { return available[:2], nil }
+	_, err = w.SelectSigners(available, 10, short)
+	assert.ErrorIs(t, err, ErrInsufficientWeight)
+}
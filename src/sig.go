@@ -0,0 +1,66 @@
+package wts
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// SigSize is the length of an encoded Sig
+const SigSize = 8 + 7*bls.SizeOfG1AffineCompressed + 2*bls.SizeOfG2AffineCompressed
+
+var ErrSigEncoding = errors.New("wts: malformed signature encoding")
+
+// MarshalBinary encodes the weight and the group elements in compressed form.
+// The challenge is left out, as Verify recomputes it.
+func (s *Sig) MarshalBinary() ([]byte, error) {
+	if s.ths < 0 {
+		return nil, fmt.Errorf("%w: weight %d", ErrSigEncoding, s.ths)
+	}
+	buf := make([]byte, 0, SigSize)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(s.ths))
+	for _, p := range []bls.G1Affine{s.bTau, s.qB, s.pTau, s.aggPk, s.aggPkB, s.pi.qTau, s.pi.rTau} {
+		b := p.Bytes()
+		buf = append(buf, b[:]...)
+	}
+	aggSig := *new(bls.G2Affine).FromJacobian(&s.aggSig)
+	for _, p := range []bls.G2Affine{s.bNegTau, aggSig} {
+		b := p.Bytes()
+		buf = append(buf, b[:]...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes an encoding of MarshalBinary, the points are checked to be in
+// their groups
+func (s *Sig) UnmarshalBinary(data []byte) error {
+	const g1Size, g2Size = bls.SizeOfG1AffineCompressed, bls.SizeOfG2AffineCompressed
+	if len(data) != SigSize {
+		return fmt.Errorf("%w: %d bytes", ErrSigEncoding, len(data))
+	}
+	var dec Sig
+	ths := binary.BigEndian.Uint64(data)
+	if ths > uint64(^uint(0)>>1) {
+		return fmt.Errorf("%w: weight %d", ErrSigEncoding, ths)
+	}
+	dec.ths = int(ths)
+	off := 8
+	for _, p := range []*bls.G1Affine{&dec.bTau, &dec.qB, &dec.pTau, &dec.aggPk, &dec.aggPkB, &dec.pi.qTau, &dec.pi.rTau} {
+		if _, err := p.SetBytes(data[off : off+g1Size]); err != nil {
+			return fmt.Errorf("%w: %v", ErrSigEncoding, err)
+		}
+		off += g1Size
+	}
+	var aggSig bls.G2Affine
+	for _, p := range []*bls.G2Affine{&dec.bNegTau, &aggSig} {
+		if _, err := p.SetBytes(data[off : off+g2Size]); err != nil {
+			return fmt.Errorf("%w: %v", ErrSigEncoding, err)
+		}
+		off += g2Size
+	}
+	dec.aggSig.FromAffine(&aggSig)
+	*s = dec
+	return nil
+}
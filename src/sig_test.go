@@ -0,0 +1,62 @@
+package wts
+
+import (
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/stretchr/testify/assert"
+)
+
+// A point of the curve outside of the prime order subgroup
+func nonSubgroupG1() bls.G1Affine {
+	var p bls.G1Affine
+	four := fp.NewElement(4)
+	for x := uint64(1); ; x++ {
+		p.X.SetUint64(x)
+		var y2 fp.Element
+		y2.Square(&p.X).Mul(&y2, &p.X).Add(&y2, &four)
+		if p.Y.Sqrt(&y2) != nil && !p.IsInSubGroup() {
+			return p
+		}
+	}
+}
+
+func TestSigEncoding(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 3
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+	w := NewWTS(n, weights, GenCRS(n))
+	w.preProcess()
+
+	signers := []int{1, 4, 6}
+	sigmas := make([]bls.G2Jac, len(signers))
+	for i, idx := range signers {
+		sigmas[i] = w.psign(msg, w.signers[idx])
+	}
+	sig, err := w.combine(signers, sigmas)
+	assert.NoError(t, err)
+
+	enc, err := sig.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, len(enc), SigSize)
+	var dec Sig
+	assert.NoError(t, dec.UnmarshalBinary(enc))
+	assert.NoError(t, w.Verify(msg, dec, 2+5+7))
+	again, err := dec.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, again, enc)
+
+	assert.ErrorIs(t, dec.UnmarshalBinary(enc[1:]), ErrSigEncoding)
+
+	// A point of the curve that is not in G1
+	p := nonSubgroupG1()
+	assert.Equal(t, p.IsOnCurve(), true)
+	pb := p.Bytes()
+	bad := append([]byte(nil), enc...)
+	copy(bad[8:], pb[:])
+	assert.ErrorIs(t, dec.UnmarshalBinary(bad), ErrSigEncoding)
+}
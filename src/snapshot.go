@@ -0,0 +1,195 @@
+package wts
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+var ErrSnapshot = errors.New("wts: invalid validator snapshot")
+
+// Validator is an entry of a stake snapshot
+type Validator struct {
+	ID     string // Optional
+	PubKey bls.G1Affine
+	Stake  *big.Int
+}
+
+// Snapshot is a validated stake snapshot. Validator i sits in slot i, i.e. at omega^i in H,
+// and the slots are assigned by sorting the compressed public keys, so they do not depend
+// on the order of the file.
+//
+// A committee takes only its weights and its slot order from a snapshot. The public keys
+// identify the validators, see RegistryFromSnapshot, but they are not the committee keys:
+// a committee is built from the hints of its signers (see SignerHints), which a snapshot
+// does not carry.
+type Snapshot struct {
+	Validators []Validator
+}
+
+// LoadSnapshot reads a snapshot from a .csv or .json file
+func LoadSnapshot(path string) (Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return LoadSnapshotCSV(f)
+	case ".json":
+		return LoadSnapshotJSON(f)
+	}
+	return Snapshot{}, fmt.Errorf("%w: unknown format of %s", ErrSnapshot, path)
+}
+
+// LoadSnapshotCSV reads a snapshot from CSV with a header row naming the columns
+// pubkey, stake and optionally id, in any order
+func LoadSnapshotCSV(r io.Reader) (Snapshot, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("%w: %v", ErrSnapshot, err)
+	}
+	if len(records) == 0 {
+		return Snapshot{}, fmt.Errorf("%w: missing header", ErrSnapshot)
+	}
+	cols := map[string]int{"id": -1, "pubkey": -1, "stake": -1}
+	for i, name := range records[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := cols[name]; ok {
+			cols[name] = i
+		}
+	}
+	if cols["pubkey"] < 0 || cols["stake"] < 0 {
+		return Snapshot{}, fmt.Errorf("%w: header needs pubkey and stake columns", ErrSnapshot)
+	}
+
+	entries := make([]snapshotEntry, len(records)-1)
+	for i, rec := range records[1:] {
+		entries[i] = snapshotEntry{PubKey: rec[cols["pubkey"]], Stake: json.Number(strings.TrimSpace(rec[cols["stake"]]))}
+		if cols["id"] >= 0 {
+			entries[i].ID = rec[cols["id"]]
+		}
+	}
+	return newSnapshot(entries)
+}
+
+// LoadSnapshotJSON reads a snapshot from a JSON array of {"pubkey", "stake", "id"} objects,
+// where stake is an integer or a decimal string
+func LoadSnapshotJSON(r io.Reader) (Snapshot, error) {
+	var entries []snapshotEntry
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := dec.Decode(&entries); err != nil {
+		return Snapshot{}, fmt.Errorf("%w: %v", ErrSnapshot, err)
+	}
+	return newSnapshot(entries)
+}
+
+type snapshotEntry struct {
+	ID     string      `json:"id"`
+	PubKey string      `json:"pubkey"` // Hex of the compressed G1 point
+	Stake  json.Number `json:"stake"`
+}
+
+// Validates the entries and assigns the slots
+func newSnapshot(entries []snapshotEntry) (Snapshot, error) {
+	if len(entries) == 0 {
+		return Snapshot{}, fmt.Errorf("%w: no validators", ErrSnapshot)
+	}
+	vals := make([]Validator, len(entries))
+	keys := make([][]byte, len(entries))
+	seenKeys := make(map[string]int, len(entries))
+	seenIDs := make(map[string]int, len(entries))
+	for i, e := range entries {
+		keyHex := strings.TrimPrefix(strings.TrimSpace(e.PubKey), "0x")
+		key, err := hex.DecodeString(keyHex)
+		if err != nil || len(key) != bls.SizeOfG1AffineCompressed {
+			return Snapshot{}, fmt.Errorf("%w: entry %d: public key is not a compressed G1 point", ErrSnapshot, i)
+		}
+		if _, err := vals[i].PubKey.SetBytes(key); err != nil {
+			return Snapshot{}, fmt.Errorf("%w: entry %d: %v", ErrSnapshot, i, err)
+		}
+		if vals[i].PubKey.IsInfinity() {
+			return Snapshot{}, fmt.Errorf("%w: entry %d: public key is the identity", ErrSnapshot, i)
+		}
+		if j, ok := seenKeys[string(key)]; ok {
+			return Snapshot{}, fmt.Errorf("%w: entries %d and %d have the same public key", ErrSnapshot, j, i)
+		}
+		seenKeys[string(key)] = i
+		keys[i] = key
+
+		stake, ok := new(big.Int).SetString(string(e.Stake), 10)
+		if !ok || stake.Sign() < 0 {
+			return Snapshot{}, fmt.Errorf("%w: entry %d: stake %q is not a non-negative integer", ErrSnapshot, i, e.Stake)
+		}
+		vals[i].Stake = stake
+
+		vals[i].ID = strings.TrimSpace(e.ID)
+		if vals[i].ID != "" {
+			if j, ok := seenIDs[vals[i].ID]; ok {
+				return Snapshot{}, fmt.Errorf("%w: entries %d and %d have the same id", ErrSnapshot, j, i)
+			}
+			seenIDs[vals[i].ID] = i
+		}
+	}
+
+	perm := GetRange(0, len(vals))
+	sort.Slice(perm, func(i, j int) bool {
+		return bytes.Compare(keys[perm[i]], keys[perm[j]]) < 0
+	})
+	s := Snapshot{Validators: make([]Validator, len(vals))}
+	for slot, i := range perm {
+		s.Validators[slot] = vals[i]
+	}
+	return s, nil
+}
+
+// Slots is the committee size for the snapshot, the smallest power of two that fits
+// the validators. The slots past the validators are padding with zero stake.
+func (s *Snapshot) Slots() int {
+	if len(s.Validators) <= 1 {
+		return 1
+	}
+	return 1 << bits.Len(uint(len(s.Validators)-1))
+}
+
+// Stakes returns the stake of every slot
+func (s *Snapshot) Stakes() []*big.Int {
+	stakes := make([]*big.Int, s.Slots())
+	for i := range stakes {
+		stakes[i] = new(big.Int)
+		if i < len(s.Validators) {
+			stakes[i].Set(s.Validators[i].Stake)
+		}
+	}
+	return stakes
+}
+
+// Weights returns the stakes of every slot as weights. Stakes that do not fit in an int
+// need to be mapped with QuantizeStakes instead.
+func (s *Snapshot) Weights() ([]int, error) {
+	weights := make([]int, s.Slots())
+	total := new(big.Int)
+	for i, v := range s.Validators {
+		total.Add(total, v.Stake)
+		if !total.IsInt64() || total.Int64() > int64(^uint(0)>>1) {
+			return nil, fmt.Errorf("%w: total stake does not fit in an int", ErrStakes)
+		}
+		weights[i] = int(v.Stake.Int64())
+	}
+	return weights, nil
+}
@@ -0,0 +1,96 @@
+package wts
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSnapshot(t *testing.T) {
+	_, _, g1, _ := bls.Generators()
+	n := 5
+	keys := make([]string, n)
+	for i := range keys {
+		sk, _ := RandomElement(rand.Reader)
+		var pk bls.G1Affine
+		pk.ScalarMultiplication(&g1, sk.BigInt(&big.Int{}))
+		b := pk.Bytes()
+		keys[i] = hex.EncodeToString(b[:])
+	}
+
+	csvRows := []string{"stake,pubkey,id"}
+	jsonRows := []string{}
+	for i := n - 1; i >= 0; i-- {
+		csvRows = append(csvRows, fmt.Sprintf("%d,%s,v%d", 10*(i+1), keys[i], i))
+		jsonRows = append(jsonRows, fmt.Sprintf(`{"id": "v%d", "pubkey": "0x%s", "stake": "%d"}`, i, keys[i], 10*(i+1)))
+	}
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "snapshot.csv")
+	jsonPath := filepath.Join(dir, "snapshot.json")
+	assert.NoError(t, os.WriteFile(csvPath, []byte(strings.Join(csvRows, "\n")), 0o644))
+	assert.NoError(t, os.WriteFile(jsonPath, []byte("["+strings.Join(jsonRows, ",")+"]"), 0o644))
+
+	fromCSV, err := LoadSnapshot(csvPath)
+	assert.NoError(t, err)
+	fromJSON, err := LoadSnapshot(jsonPath)
+	assert.NoError(t, err)
+	assert.Equal(t, fromCSV, fromJSON)
+
+	// Slots follow the sorted public keys
+	for i := 1; i < n; i++ {
+		prev, cur := fromCSV.Validators[i-1].PubKey.Bytes(), fromCSV.Validators[i].PubKey.Bytes()
+		assert.Equal(t, hex.EncodeToString(prev[:]) < hex.EncodeToString(cur[:]), true)
+	}
+	assert.Equal(t, fromCSV.Slots(), 8)
+	weights, err := fromCSV.Weights()
+	assert.NoError(t, err)
+	assert.Equal(t, len(weights), 8)
+	for i, v := range fromCSV.Validators {
+		var idx int
+		fmt.Sscanf(v.ID, "v%d", &idx)
+		assert.Equal(t, weights[i], 10*(idx+1))
+	}
+	assert.Equal(t, weights[n:], []int{0, 0, 0})
+
+	w := NewWTS(fromCSV.Slots(), weights, GenCRS(fromCSV.Slots()))
+	assert.Equal(t, w.n, 8)
+	reg, err := RegistryFromSnapshot(&fromCSV)
+	assert.NoError(t, err)
+	assert.NoError(t, w.SetRegistry(reg))
+	ids, err := reg.IDs([]int{0})
+	assert.NoError(t, err)
+	assert.Equal(t, ids[0], fromCSV.Validators[0].ID)
+
+	bad := []string{
+		"pubkey,id\n" + keys[0] + ",a",
+		"pubkey,stake\n" + keys[0][2:] + ",1",
+		"pubkey,stake\n" + keys[0] + ",-1",
+		"pubkey,stake\n" + keys[0] + ",1\n" + keys[0] + ",2",
+		"pubkey,stake,id\n" + keys[0] + ",1,a\n" + keys[1] + ",2,a",
+		"pubkey,stake\n" + "c0" + strings.Repeat("00", 47) + ",1",
+		"pubkey,stake\n",
+	}
+	for _, in := range bad {
+		_, err := LoadSnapshotCSV(strings.NewReader(in))
+		assert.ErrorIs(t, err, ErrSnapshot)
+	}
+	_, err = LoadSnapshotJSON(strings.NewReader(`[{"pubkey": "` + keys[0] + `", "stake": 1.5}]`))
+	assert.ErrorIs(t, err, ErrSnapshot)
+
+	_, err = LoadSnapshotJSON(strings.NewReader(`[{"pubkey": "` + keys[0] + `", "stake": 1e30}, {"pubkey": "` + keys[1] + `", "stake": "100000000000000000000000000000"}]`))
+	assert.ErrorIs(t, err, ErrSnapshot)
+	huge, err := LoadSnapshotJSON(strings.NewReader(`[{"pubkey": "` + keys[0] + `", "stake": 1}, {"pubkey": "` + keys[1] + `", "stake": "100000000000000000000000000000"}]`))
+	assert.NoError(t, err)
+	_, err = huge.Weights()
+	assert.ErrorIs(t, err, ErrStakes)
+	_, err = QuantizeStakes(huge.Stakes(), 64)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,229 @@
+package wts
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+var ErrCommitteeSize = errors.New("wts: committee size must be a power of two between 2 and the size of the universal CRS")
+
+// UniversalCRS is generated once for a maximum committee size N.
+// The CRS of any committee whose size is a power of two n <= N is derived from it with Derive,
+// which only uses public values, so a single ceremony serves every committee size.
+// The powers of h are published in G2 as well, so that a derived CRS can shift them by N-n.
+type UniversalCRS struct {
+	N int
+	// Generators
+	g1    bls.G1Jac
+	g2    bls.G2Jac
+	g1a   bls.G1Affine
+	g2a   bls.G2Affine
+	g1B   bls.G1Jac
+	g2Ba  bls.G2Affine
+	tau   fr.Element     // FIXME: To remove, only added for testing purposes
+	PoT   []bls.G1Affine // [g^{tau^i}] for i < N
+	PoTH  []bls.G1Affine // [h^{tau^i}] for i < N
+	PoT2  []bls.G2Affine // [g2^{tau^i}] for i <= N
+	PoTH2 []bls.G2Affine // [h2^{tau^i}] for i < N
+}
+
+func GenUniversalCRS(N int) UniversalCRS {
+	u, err := GenUniversalCRSWithRand(N, rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// GenUniversalCRSWithRand is GenUniversalCRS with the trapdoors sampled from rnd.
+// The trapdoors are drawn as in GenCRSWithRand, so for the same randomness
+// Derive(n) returns the CRS that GenCRSWithRand(n, ...) generates.
+func GenUniversalCRSWithRand(N int, rnd io.Reader) (UniversalCRS, error) {
+	g1, g2, g1a, g2a := bls.Generators()
+
+	trapdoors, err := RandomElements(rnd, 3)
+	if err != nil {
+		return UniversalCRS{}, err
+	}
+	tau, beta, hF := trapdoors[0], trapdoors[1], trapdoors[2]
+
+	g1B := new(bls.G1Jac).ScalarMultiplication(&g1, beta.BigInt(&big.Int{}))
+	g2Ba := new(bls.G2Affine).ScalarMultiplication(&g2a, beta.BigInt(&big.Int{}))
+	h1a := new(bls.G1Affine).ScalarMultiplication(&g1a, hF.BigInt(&big.Int{}))
+	h2a := new(bls.G2Affine).ScalarMultiplication(&g2a, hF.BigInt(&big.Int{}))
+
+	poT := make([]fr.Element, N+1)
+	poT[0].SetOne()
+	for i := 1; i <= N; i++ {
+		poT[i].Mul(&poT[i-1], &tau)
+	}
+
+	return UniversalCRS{
+		N:     N,
+		g1:    g1,
+		g2:    g2,
+		g1a:   g1a,
+		g2a:   g2a,
+		g1B:   *g1B,
+		g2Ba:  *g2Ba,
+		tau:   tau,
+		PoT:   bls.BatchScalarMultiplicationG1(&g1a, poT[:N]),
+		PoTH:  bls.BatchScalarMultiplicationG1(h1a, poT[:N]),
+		PoT2:  bls.BatchScalarMultiplicationG2(&g2a, poT),
+		PoTH2: bls.BatchScalarMultiplicationG2(h2a, poT[:N]),
+	}, nil
+}
+
+// Derive returns the CRS for a committee of n signers.
+//
+// Lag_i(X) = 1/n.sum_j (X/omega^i)^j, so the Lagrange basis of H in the exponent is an
+// inverse FFT of the powers of tau. The same holds for the whole coset cH, and the
+// Lagrange basis of its first n-1 points L is Lag'_l = LagC_l - omega^{l+1}.LagC_{n-1}.
+//
+// The degree check on rTau relies on h.tau^k being unknown for k >= n. The derived CRS
+// therefore uses h' = h.tau^{N-n} in place of h, whose powers are published up to tau^{n-1}
+// only. For n = N the derived CRS is the one GenCRSWithRand generates.
+func (u *UniversalCRS) Derive(n int) (CRS, error) {
+	if n < 2 || n > u.N || n&(n-1) != 0 {
+		return CRS{}, ErrCommitteeSize
+	}
+	s := u.N - n
+	poTH := u.PoTH[s:u.N:u.N]
+	domain, H, L, coset, coExp := crsDomain(n)
+
+	nInv := fr.NewElement(uint64(n))
+	nInv.Inverse(&nInv)
+	scales := make([]fr.Element, n)
+	for j := 0; j < n; j++ {
+		scales[j] = nInv
+	}
+	lagHTaus := lagrangeG1(u.PoT[:n], scales, domain.GeneratorInv)
+	lagHTausH := lagrangeG1(poTH, scales, domain.GeneratorInv)
+	lag2HTaus := lagrangeG2(u.PoT2[:n], scales, domain.GeneratorInv)
+
+	var cInv fr.Element
+	cInv.Inverse(&coset)
+	for j := 1; j < n; j++ {
+		scales[j].Mul(&scales[j-1], &cInv)
+	}
+	lagCTaus := lagrangeG1(u.PoT[:n], scales, domain.GeneratorInv)
+	lagLTaus := bls.BatchScalarMultiplicationG1(&lagCTaus[n-1], H[1:])
+	for l := 0; l < n-1; l++ {
+		lagLTaus[l].Sub(&lagCTaus[l], &lagLTaus[l])
+	}
+
+	var vHTau bls.G2Affine
+	vHTau.Sub(&u.PoT2[n], &u.g2a)
+
+	return CRS{
+		g1:        u.g1,
+		g2:        u.g2,
+		g1a:       u.g1a,
+		g2a:       u.g2a,
+		g1B:       u.g1B,
+		g1Ba:      *new(bls.G1Affine).FromJacobian(&u.g1B),
+		g2Ba:      u.g2Ba,
+		g1InvAff:  *new(bls.G1Affine).FromJacobian(new(bls.G1Jac).Neg(&u.g1)),
+		g2InvAff:  *new(bls.G2Affine).FromJacobian(new(bls.G2Jac).Neg(&u.g2)),
+		h1a:       poTH[0],
+		h2a:       u.PoTH2[s],
+		hTauHAff:  u.PoTH2[s+1],
+		domain:    domain,
+		H:         H,
+		L:         L,
+		lagLHDiag: GetBatchLagDiag(H, coset),
+		zHLInv:    coExp,
+		tau:       u.tau,
+		g2Tau:     u.PoT2[1],
+		vHTau:     vHTau,
+		PoT:       u.PoT[:n:n],
+		PoTH:      poTH,
+		lagHTaus:  lagHTaus,
+		lagHTausH: lagHTausH,
+		lag2HTaus: lag2HTaus,
+		lagLTaus:  lagLTaus,
+		gAlpha:    u.PoT[n-1],
+	}, nil
+}
+
+// Returns [sum_j scales_j.omega^{ij}.pts_j] for every i
+func lagrangeG1(pts []bls.G1Affine, scales []fr.Element, omega fr.Element) []bls.G1Affine {
+	a := make([]bls.G1Jac, len(pts))
+	parallelFor(len(pts), func(j int) {
+		a[j].FromAffine(&pts[j])
+		a[j].ScalarMultiplication(&a[j], scales[j].BigInt(&big.Int{}))
+	})
+	fftGroup(a, omega)
+	return bls.BatchJacobianToAffineG1(a)
+}
+
+// Returns [sum_j scales_j.omega^{ij}.pts_j] for every i
+func lagrangeG2(pts []bls.G2Affine, scales []fr.Element, omega fr.Element) []bls.G2Affine {
+	a := make([]bls.G2Jac, len(pts))
+	parallelFor(len(pts), func(j int) {
+		a[j].FromAffine(&pts[j])
+		a[j].ScalarMultiplication(&a[j], scales[j].BigInt(&big.Int{}))
+	})
+	fftGroup(a, omega)
+	res := make([]bls.G2Affine, len(a))
+	parallelFor(len(a), func(i int) {
+		res[i].FromJacobian(&a[i])
+	})
+	return res
+}
+
+// Jacobian points of G1 or G2
+type jacobian[T any] interface {
+	*T
+	ScalarMultiplication(*T, *big.Int) *T
+	AddAssign(*T) *T
+	SubAssign(*T) *T
+}
+
+// fftGroup evaluates in place the polynomial with coefficients a (in G1 or G2) at the powers of omega.
+// len(a) must be a power of two and omega a primitive len(a)-th root of unity.
+func fftGroup[T any, P jacobian[T]](a []T, omega fr.Element) {
+	n := len(a)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		half := size / 2
+		var wm fr.Element
+		wm.Exp(omega, big.NewInt(int64(n/size)))
+		twiddles := make([]big.Int, half)
+		var tw fr.Element
+		tw.SetOne()
+		for j := 0; j < half; j++ {
+			tw.BigInt(&twiddles[j])
+			tw.Mul(&tw, &wm)
+		}
+
+		parallelFor(n/2, func(k int) {
+			j := k % half
+			i := (k/half)*size + j
+			var t T
+			if j == 0 {
+				t = a[i+half]
+			} else {
+				P(&t).ScalarMultiplication(&a[i+half], &twiddles[j])
+			}
+			a[i+half] = a[i]
+			P(&a[i+half]).SubAssign(&t)
+			P(&a[i]).AddAssign(&t)
+		})
+	}
+}
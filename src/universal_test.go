@@ -0,0 +1,188 @@
+package wts
+
+import (
+	"math/big"
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUniversalCRS(t *testing.T) {
+	N := 1 << 5
+	u, err := GenUniversalCRSWithRand(N, NewSeededReader([]byte("universal")))
+	assert.NoError(t, err)
+
+	// Below N the powers of h are those of GenCRSWithRand shifted by tau^{N-n}
+	for _, n := range []int{2, 1 << 3, N} {
+		crs, err := u.Derive(n)
+		assert.NoError(t, err)
+		direct, err := GenCRSWithRand(n, NewSeededReader([]byte("universal")))
+		assert.NoError(t, err)
+
+		var shift fr.Element
+		shift.Exp(u.tau, big.NewInt(int64(N-n)))
+		s := shift.BigInt(&big.Int{})
+		direct.h1a.ScalarMultiplication(&direct.h1a, s)
+		direct.h2a.ScalarMultiplication(&direct.h2a, s)
+		direct.hTauHAff.ScalarMultiplication(&direct.hTauHAff, s)
+		direct.PoTH = bls.BatchScalarMultiplicationG1(&direct.h1a, powers(u.tau, n))
+		for i := range direct.lagHTausH {
+			direct.lagHTausH[i].ScalarMultiplication(&direct.lagHTausH[i], s)
+		}
+		assert.Equal(t, crsBytes(direct), crsBytes(crs), n)
+	}
+
+	for _, n := range []int{0, 1, 3, 2 * N} {
+		_, err := u.Derive(n)
+		assert.ErrorIs(t, err, ErrCommitteeSize)
+	}
+}
+
+func TestUniversalWTS(t *testing.T) {
+	msg := []byte("hello world")
+	u := GenUniversalCRS(1 << 5)
+
+	for _, n := range []int{1 << 3, 1 << 4} {
+		weights := make([]int, n)
+		for i := 0; i < n; i++ {
+			weights[i] = i
+		}
+		crs, err := u.Derive(n)
+		assert.NoError(t, err)
+		w := NewWTS(n, weights, crs)
+		w.preProcess()
+
+		signers := GetRangeTo(n)
+		sigmas := make([]bls.G2Jac, n)
+		ths := 0
+		for i := 0; i < n; i++ {
+			sigmas[i] = w.psign(msg, w.signers[i])
+			ths += weights[i]
+		}
+		sig, err := w.combine(signers, sigmas)
+		assert.NoError(t, err)
+		assert.Equal(t, w.gverify(msg, sig, ths), true)
+	}
+}
+
+func powers(x fr.Element, n int) []fr.Element {
+	pows := make([]fr.Element, n)
+	pows[0].SetOne()
+	for i := 1; i < n; i++ {
+		pows[i].Mul(&pows[i-1], &x)
+	}
+	return pows
+}
+
+// A single signer of weight 1 claiming the weight of the whole committee. The forgery moves
+// the missing weight into an rTau of degree n-1 and needs h.tau^n to fix up pTau.
+func TestUniversalWeightForgery(t *testing.T) {
+	msg := []byte("hello world")
+	N, n := 1<<4, 1<<3
+	u := GenUniversalCRS(N)
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = 1
+	}
+	crs, err := u.Derive(n)
+	assert.NoError(t, err)
+
+	// The derived CRS as it was before the powers of h were shifted
+	unshifted := crs
+	unshifted.h1a = u.PoTH[0]
+	unshifted.h2a = u.PoTH2[0]
+	unshifted.hTauHAff = u.PoTH2[1]
+	unshifted.PoTH = u.PoTH[:n:n]
+	nInv := fr.NewElement(uint64(n))
+	nInv.Inverse(&nInv)
+	scales := make([]fr.Element, n)
+	for i := range scales {
+		scales[i] = nInv
+	}
+	unshifted.lagHTausH = lagrangeG1(u.PoTH[:n], scales, crs.domain.GeneratorInv)
+
+	forge := func(crs CRS) (*WTS, Sig) {
+		w := NewWTS(n, weights, crs)
+		w.preProcess()
+
+		j := 3
+		var qTau, pTau bls.G1Jac
+		qTau.FromAffine(&w.pp.qTaus[j])
+		pTau = w.pp.hTausH[j]
+		rTau := w.secretPf([]int{j})
+		qwTau, rwTau, pwTauH := w.weightsPf([]int{j}, w.weights)
+
+		var b2Tau, bNegTau bls.G2Jac
+		b2Tau.FromAffine(&w.crs.lag2HTaus[j])
+		bNegTau = w.crs.g2
+		bNegTau.SubAssign(&b2Tau)
+		sig := Sig{
+			bTau:    w.crs.lagHTaus[j],
+			bNegTau: *new(bls.G2Affine).FromJacobian(&bNegTau),
+			aggPk:   w.pp.pKeys[j],
+			aggPkB:  w.pp.pKeysB[j],
+			aggSig:  w.psign(msg, w.signers[j]),
+			ths:     n,
+		}
+		xi := w.getFSChal(w.CommitteeID(), []bls.G1Affine{w.pp.pComm, w.pp.wTau, sig.bTau, sig.aggPk}, sig.ths)
+		xiInt := xi.BigInt(&big.Int{})
+		qTau.AddAssign(qwTau.ScalarMultiplication(&qwTau, xiInt))
+		rTau.AddAssign(rwTau.ScalarMultiplication(&rwTau, xiInt))
+		pTau.AddAssign(pwTauH.ScalarMultiplication(&pwTauH, xiInt))
+
+		// delta/n with delta = xi.(n-1), the weight missing from mu
+		var d fr.Element
+		d.SetUint64(uint64(n - 1))
+		d.Mul(&d, &xi).Mul(&d, &nInv)
+		dInt := d.BigInt(&big.Int{})
+		var t bls.G1Jac
+		t.FromAffine(&w.crs.g1a)
+		qTau.AddAssign(t.ScalarMultiplication(&t, dInt))
+		t.FromAffine(&u.PoT[n-1])
+		rTau.SubAssign(t.ScalarMultiplication(&t, dInt))
+		var hTauN bls.G1Affine
+		hTauN.Sub(&u.PoTH[n], &u.PoTH[0])
+		t.FromAffine(&hTauN)
+		pTau.SubAssign(t.ScalarMultiplication(&t, dInt))
+
+		sig.qB = w.binaryPf([]int{j})
+		sig.pi = IPAProof{
+			qTau: *new(bls.G1Affine).FromJacobian(&qTau),
+			rTau: *new(bls.G1Affine).FromJacobian(&rTau),
+		}
+		sig.pTau = *new(bls.G1Affine).FromJacobian(&pTau)
+		return &w, sig
+	}
+
+	w, sig := forge(unshifted)
+	assert.NoError(t, w.Verify(msg, sig, n))
+
+	w, sig = forge(crs)
+	assert.ErrorIs(t, w.Verify(msg, sig, n), ErrRTauDegree)
+}
+
+func TestFFTGroup(t *testing.T) {
+	n := 1 << 4
+	g1, _, _, _ := bls.Generators()
+
+	coeffs := make([]fr.Element, n)
+	points := make([]bls.G1Jac, n)
+	for i := 0; i < n; i++ {
+		coeffs[i].SetRandom()
+		points[i].ScalarMultiplication(&g1, coeffs[i].BigInt(&big.Int{}))
+	}
+
+	dom := GetDomain(uint64(n))
+	fftGroup(points, dom.Generator)
+	dom.FFT(coeffs, fft.DIF)
+	fft.BitReverse(coeffs)
+
+	var exp bls.G1Jac
+	for i := 0; i < n; i++ {
+		exp.ScalarMultiplication(&g1, coeffs[i].BigInt(&big.Int{}))
+		assert.Equal(t, exp.Equal(&points[i]), true)
+	}
+}
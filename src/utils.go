@@ -2,6 +2,9 @@ package wts
 
 import (
 	"math/big"
+	"runtime"
+	"strconv"
+	"sync"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
@@ -9,9 +12,10 @@ import (
 	"golang.org/x/exp/constraints"
 )
 
+// Domains are shared by every instance in the process, they are only read once built
 var (
-	zeros   []fr.Element
-	domains = make(map[uint64]*fft.Domain)
+	domainsMu sync.RWMutex
+	domains   = make(map[uint64]*fft.Domain)
 )
 
 type Message []byte
@@ -230,11 +234,75 @@ func GetBatchLag(L, H []fr.Element) [][]fr.Element {
 	return lagLH
 }
 
+// GetCosetLagAt returns the lagrange coefficients at a point for the first m points of the coset coset*omegas.
+// It starts from Z(X) = X^N - coset^N for the whole coset and then removes the N-m points that are left out,
+// so it takes O(N + m(N-m)) time instead of the O(m^2) of GetLagAtSlow.
+func GetCosetLagAt(omegas []fr.Element, coset, at fr.Element, m int) []fr.Element {
+	N := len(omegas)
+	points := make([]fr.Element, N)
+	for i := 0; i < N; i++ {
+		points[i].Mul(&coset, &omegas[i])
+	}
+
+	// Z(at) = at^N - coset^N
+	var cN, Zat fr.Element
+	cN.Exp(coset, big.NewInt(int64(N)))
+	Zat.Exp(at, big.NewInt(int64(N)))
+	Zat.Sub(&Zat, &cN)
+
+	// Batch inversion for 1/(at - p_l) over the whole coset and 1/(N coset^N)
+	invs := make([]fr.Element, N+1)
+	for l := 0; l < N; l++ {
+		invs[l].Sub(&at, &points[l])
+	}
+	invs[N].SetUint64(uint64(N))
+	invs[N].Mul(&invs[N], &cN)
+	invs = fr.BatchInvert(invs)
+
+	// Lag_l(at) over the whole coset is Z(at)/((at - p_l) Z'(p_l)), with Z'(p_l) = N p_l^{N-1} = N coset^N / p_l
+	var scale, diff fr.Element
+	scale.Mul(&Zat, &invs[N])
+
+	results := make([]fr.Element, m)
+	for l := 0; l < m; l++ {
+		results[l].Mul(&scale, &points[l]).Mul(&results[l], &invs[l])
+		// Leaving out p_k multiplies by (p_l - p_k)/(at - p_k)
+		for k := m; k < N; k++ {
+			diff.Sub(&points[l], &points[k])
+			results[l].Mul(&results[l], &diff).Mul(&results[l], &invs[k])
+		}
+	}
+	return results
+}
+
+// GetBatchLagDiag is GetBatchLag for L = coset*H, where Lag_i(L_l) only depends on (l-i) mod |H|.
+// It returns d with Lag_i(L_l) = d[(l-i) mod |H|] = (coset^N - 1)/(N (coset omega^{l-i} - 1)).
+func GetBatchLagDiag(omegas []fr.Element, coset fr.Element) []fr.Element {
+	N := len(omegas)
+	one := fr.One()
+
+	var K fr.Element
+	K.Exp(coset, big.NewInt(int64(N)))
+	K.Sub(&K, &one)
+
+	d := make([]fr.Element, N)
+	for k := 0; k < N; k++ {
+		d[k].Mul(&coset, &omegas[k]).Sub(&d[k], &one)
+		d[k].Mul(&d[k], new(fr.Element).SetUint64(uint64(N)))
+	}
+	d = fr.BatchInvert(d)
+	for k := 0; k < N; k++ {
+		d[k].Mul(&d[k], &K)
+	}
+	return d
+}
+
+// GetOmega deterministically derives a candidate n-th root of unity from seed
 func GetOmega(n, seed int) fr.Element {
-	var x, y, z fr.Element
+	var y, z fr.Element
 	var nF, nFNegInv fr.Element
 
-	x.SetRandom()
+	x, _ := RandomElement(NewSeededReader([]byte(strconv.Itoa(seed))))
 	nF = fr.NewElement(uint64(n))
 
 	nFNegInv.Neg(&nF)
@@ -327,12 +395,22 @@ func EvaluatePoly(pol []fr.Element, val fr.Element) fr.Element {
 	return res
 }
 
+// GetDomain returns the cached FFT domain of size NextPowerOfTwo(m). It is safe for concurrent use.
 func GetDomain(m uint64) *fft.Domain {
 	n := ecc.NextPowerOfTwo(uint64(m))
+	domainsMu.RLock()
+	dom, ok := domains[n]
+	domainsMu.RUnlock()
+	if ok {
+		return dom
+	}
+
+	domainsMu.Lock()
+	defer domainsMu.Unlock()
 	if dom, ok := domains[n]; ok {
 		return dom
 	}
-	dom := fft.NewDomain(n)
+	dom = fft.NewDomain(n)
 	domains[n] = dom
 	return dom
 }
@@ -349,14 +427,28 @@ func GetRangeTo[T constraints.Integer](to T) []T {
 	return GetRange(0, to)
 }
 
-func GetZeros(n uint64) []fr.Element {
-	if n < uint64(len(zeros)) {
-		return zeros[:n]
+// parallelFor calls f(i) for every i in [0, n) spreading the calls over the available cores
+func parallelFor(n int, f func(i int)) {
+	workers := runtime.NumCPU()
+	if workers > n {
+		workers = n
 	}
-	for uint64(len(zeros)) < n {
-		zeros = append(zeros, fr.NewElement(0))
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for k := 0; k < workers; k++ {
+		go func(k int) {
+			defer wg.Done()
+			for i := k; i < n; i += workers {
+				f(i)
+			}
+		}(k)
 	}
-	return zeros[:n]
+	wg.Wait()
+}
+
+// GetZeros returns a fresh slice of n zeros that the caller is free to modify
+func GetZeros(n uint64) []fr.Element {
+	return make([]fr.Element, n)
 }
 
 func elementsString(e []fr.Element) string {
@@ -87,6 +87,50 @@ func TestGetLagAt0(t *testing.T) {
 	}
 }
 
+func TestGetCosetLagAt(t *testing.T) {
+	n := 16
+	omegas := RootsOfUnity(uint64(n))
+	coset := fr.NewElement(7)
+	var tau fr.Element
+	tau.SetRandom()
+
+	for _, m := range []int{n, n - 1, n - 3} {
+		points := make([]fr.Element, m)
+		for i := 0; i < m; i++ {
+			points[i].Mul(&coset, &omegas[i])
+		}
+		expected := GetLagAtSlow(tau, points)
+		actual := GetCosetLagAt(omegas, coset, tau, m)
+
+		for i := 0; i < len(expected); i++ {
+			if !actual[i].Equal(&expected[i]) {
+				t.Errorf("m=%d %d: Expected %s, got %s", m, i, expected[i].String(), actual[i].String())
+			}
+		}
+	}
+}
+
+func TestGetBatchLagDiag(t *testing.T) {
+	n := 16
+	omegas := RootsOfUnity(uint64(n))
+	coset := fr.NewElement(2)
+	L := make([]fr.Element, n-1)
+	for i := 0; i < n-1; i++ {
+		L[i].Mul(&coset, &omegas[i])
+	}
+
+	expected := GetBatchLag(L, omegas)
+	diag := GetBatchLagDiag(omegas, coset)
+	for l := 0; l < n-1; l++ {
+		for i := 0; i < n; i++ {
+			actual := diag[((l-i)%n+n)%n]
+			if !actual.Equal(&expected[l][i]) {
+				t.Errorf("(%d, %d): Expected %s, got %s", l, i, expected[l][i].String(), actual.String())
+			}
+		}
+	}
+}
+
 func TestGetCoefficientsFromRoots(t *testing.T) {
 	// (X-1)(X-2)(X-3)(X-4)(X-5)
 	roots := []fr.Element{newElem(1), newElem(2), newElem(3), newElem(4), newElem(5)}
@@ -0,0 +1,59 @@
+package wts
+
+import (
+	"errors"
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyErrors(t *testing.T) {
+	msg := []byte("hello world")
+	n := 1 << 4
+	weights := make([]int, n)
+	for i := 0; i < n; i++ {
+		weights[i] = i + 1
+	}
+
+	w := NewWTS(n, weights, GenCRS(n))
+	w.preProcess()
+
+	signers := GetRange(0, n/2)
+	sigmas := make([]bls.G2Jac, len(signers))
+	ths := 0
+	for i, idx := range signers {
+		sigmas[i] = w.psign(msg, w.signers[idx])
+		ths += weights[idx]
+	}
+	sig, err := w.combine(signers, sigmas)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Verify(msg, sig, ths))
+
+	err = w.Verify(msg, sig, ths+1)
+	assert.ErrorIs(t, err, ErrInsufficientWeight)
+	var vErr *VerifyError
+	assert.Equal(t, errors.As(err, &vErr), true)
+	assert.Equal(t, ths, vErr.Claimed)
+	assert.Equal(t, ths+1, vErr.Required)
+
+	assert.ErrorIs(t, w.Verify([]byte("other message"), sig, ths), ErrAggSig)
+
+	tampered := []struct {
+		err    error
+		tamper func(s *Sig)
+	}{
+		{ErrAggPkDegree, func(s *Sig) { s.aggPkB = w.crs.g1a }},
+		{ErrBitvector, func(s *Sig) { s.bTau = w.crs.g1a }},
+		{ErrBinary, func(s *Sig) { s.qB = w.crs.g1a }},
+		{ErrInnerProduct, func(s *Sig) { s.pi.qTau = w.crs.g1a }},
+		{ErrRTauDegree, func(s *Sig) { s.pTau = w.crs.g1a }},
+	}
+	for _, tc := range tampered {
+		bad := sig
+		tc.tamper(&bad)
+		err := w.Verify(msg, bad, ths)
+		assert.ErrorIs(t, err, tc.err)
+		assert.Equal(t, w.gverify(msg, bad, ths), false)
+	}
+}
@@ -1,9 +1,14 @@
 package wts
 
 import (
+	"context"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/binary"
+	"fmt"
+	"io"
 	"math/big"
+	"sort"
 	"sync"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -55,7 +60,7 @@ type CRS struct {
 	domain    *fft.Domain
 	H         []fr.Element
 	L         []fr.Element
-	lagLH     [][]fr.Element
+	lagLHDiag []fr.Element // Lag_i(L_l) = lagLHDiag[(l-i) mod n]
 	zHLInv    fr.Element
 	g2Tau     bls.G2Affine
 	vHTau     bls.G2Affine
@@ -71,6 +76,7 @@ type CRS struct {
 type Params struct {
 	pComm  bls.G1Affine     // com(g^s_i)
 	wTau   bls.G1Affine     // com(weights)
+	wTaus  []bls.G1Affine   // com(dims[d])
 	pKeys  []bls.G1Affine   // [g^s_i]
 	pKeysB []bls.G1Affine   // [g^{beta s_i}]
 	qTaus  []bls.G1Affine   // [g^{s_i.q_i(tau)}]
@@ -83,21 +89,50 @@ type Params struct {
 	wqrTaus []bls.G1Affine
 }
 
+// A WTS is read-only after preProcess, so combine and gverify may be called concurrently
 type WTS struct {
-	weights []int   // Weight distribution
-	n       int     // Total number of signers
-	signers []Party // List of signers
-	crs     CRS     // CRS for the protocol
-	pp      Params  // The parameters for the signatures
+	weights []int     // Weight distribution
+	dims    [][]int   // Further weight vectors, see NewMultiWeightWTS
+	n       int       // Total number of signers
+	signers []Party   // List of signers
+	crs     CRS       // CRS for the protocol
+	pp      Params    // The parameters for the signatures
+	rnd     io.Reader // Source of randomness
+	epoch   uint64    // Epoch bound into the CommitteeID
+	// Maps signer ids to slots, bound into the CommitteeID when set
+	registry       *Registry
+	registryDigest *[32]byte   // Digest of registry when it was set
+	crsID          [32]byte    // ID of crs
+	cid            CommitteeID // Recomputed by updateCommitteeID whenever one of its inputs changes
 }
 
 func GenCRS(n int) CRS {
+	crs, err := GenCRSWithRand(n, rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return crs
+}
+
+// GenCRSWithRand is GenCRS with the trapdoors sampled from rnd
+func GenCRSWithRand(n int, rnd io.Reader) (CRS, error) {
+	return GenCRSContext(context.Background(), n, rnd, nil)
+}
+
+// GenCRSContext is GenCRSWithRand that reports its progress to progress, which may be nil,
+// and stops with ctx.Err() when ctx is done
+func GenCRSContext(ctx context.Context, n int, rnd io.Reader, progress ProgressFunc) (CRS, error) {
+	if err := ctx.Err(); err != nil {
+		return CRS{}, err
+	}
+	tr := newTracker(ctx, progress, PhaseCRS, 7)
 	g1, g2, g1a, g2a := bls.Generators()
 
-	var tau, beta, hF fr.Element
-	tau.SetRandom()
-	beta.SetRandom()
-	hF.SetRandom()
+	trapdoors, err := RandomElements(rnd, 3)
+	if err != nil {
+		return CRS{}, err
+	}
+	tau, beta, hF := trapdoors[0], trapdoors[1], trapdoors[2]
 	tauH := *new(fr.Element).Mul(&tau, &hF)
 
 	g1B := new(bls.G1Jac).ScalarMultiplication(&g1, beta.BigInt(&big.Int{}))
@@ -108,31 +143,8 @@ func GenCRS(n int) CRS {
 	h2a := new(bls.G2Affine).ScalarMultiplication(&g2a, hF.BigInt(&big.Int{}))
 	hTauHAff := new(bls.G2Affine).ScalarMultiplication(&g2a, tauH.BigInt(&big.Int{}))
 
-	domain := GetDomain(uint64(n))
-	omH := domain.Generator
-	H := make([]fr.Element, n)
-	H[0].SetOne()
-	for i := 1; i < n; i++ {
-		H[i].Mul(&omH, &H[i-1])
-	}
-
-	// OPT: Can we work with a better coset?
+	domain, H, L, coset, coExp := crsDomain(n)
 	one := fr.One()
-	var coset, coExp fr.Element
-	for i := 2; i < n+2; i++ {
-		coset = fr.NewElement(uint64(i))
-		coExp.Exp(coset, big.NewInt(int64(n)))
-		if !coExp.Equal(&one) {
-			break
-		}
-	}
-	coExp.Sub(&coExp, &one)
-	coExp.Inverse(&coExp)
-
-	L := make([]fr.Element, n-1)
-	for i := 0; i < n-1; i++ {
-		L[i].Mul(&coset, &H[i])
-	}
 
 	poT := make([]fr.Element, n)
 	poT[0].SetOne()
@@ -140,7 +152,13 @@ func GenCRS(n int) CRS {
 		poT[i].Mul(&poT[i-1], &tau)
 	}
 	PoT := bls.BatchScalarMultiplicationG1(&g1a, poT)
+	if err := tr.step(); err != nil {
+		return CRS{}, err
+	}
 	PoTH := bls.BatchScalarMultiplicationG1(h1a, poT)
+	if err := tr.step(); err != nil {
+		return CRS{}, err
+	}
 
 	// Computing vHTau
 	var tauN fr.Element
@@ -150,21 +168,29 @@ func GenCRS(n int) CRS {
 
 	// Computing Lagrange in the exponent
 	lagH := GetAllLagAtWithOmegas(H, tau)
-	// OPT: Current implementation of GetLagAt is quadratic, we can make it O(nlogn)
-	lagL := GetLagAtSlow(tau, L) // OPT: Can we reuse the denominators from GetLag(tau,H)?
+	lagL := GetCosetLagAt(H, coset, tau, n-1)
+	if err := tr.step(); err != nil {
+		return CRS{}, err
+	}
 	lagHTaus := bls.BatchScalarMultiplicationG1(&g1a, lagH)
+	if err := tr.step(); err != nil {
+		return CRS{}, err
+	}
 	lagHTausH := bls.BatchScalarMultiplicationG1(h1a, lagH)
+	if err := tr.step(); err != nil {
+		return CRS{}, err
+	}
 	lag2HTaus := bls.BatchScalarMultiplicationG2(&g2a, lagH)
+	if err := tr.step(); err != nil {
+		return CRS{}, err
+	}
 	lagLTaus := bls.BatchScalarMultiplicationG1(&g1a, lagL)
-
-	// Computing g^alpha
-	var alpha, div fr.Element
-	for i := 0; i < n; i++ {
-		alpha.Add(&alpha, div.Div(&lagH[i], &H[i]))
+	if err := tr.step(); err != nil {
+		return CRS{}, err
 	}
-	gAlpha := new(bls.G1Jac).ScalarMultiplication(&g1, alpha.BigInt(&big.Int{}))
 
-	lagLH := GetBatchLag(L, H)
+	// Computing g^alpha, alpha = sum_i Lag_i(tau)/omega^i interpolates X^{-1} = X^{n-1} over H
+	gAlpha := PoT[n-1]
 
 	return CRS{
 		g1:        g1,
@@ -182,7 +208,7 @@ func GenCRS(n int) CRS {
 		domain:    domain,
 		H:         H,
 		L:         L,
-		lagLH:     lagLH,
+		lagLHDiag: GetBatchLagDiag(H, coset),
 		zHLInv:    coExp,
 		tau:       tau,
 		g2Tau:     *new(bls.G2Affine).FromJacobian(g2Tau),
@@ -193,57 +219,170 @@ func GenCRS(n int) CRS {
 		lagHTausH: lagHTausH,
 		lag2HTaus: lag2HTaus,
 		lagLTaus:  lagLTaus,
-		gAlpha:    *new(bls.G1Affine).FromJacobian(gAlpha),
+		gAlpha:    gAlpha,
+	}, nil
+}
+
+// Returns the domain H of size n, the first n-1 points L of the coset used for the
+// lagLTaus, the coset shift and 1/(coset^n-1)
+func crsDomain(n int) (*fft.Domain, []fr.Element, []fr.Element, fr.Element, fr.Element) {
+	domain := GetDomain(uint64(n))
+	omH := domain.Generator
+	H := make([]fr.Element, n)
+	H[0].SetOne()
+	for i := 1; i < n; i++ {
+		H[i].Mul(&omH, &H[i-1])
 	}
+
+	// OPT: Can we work with a better coset?
+	one := fr.One()
+	var coset, coExp fr.Element
+	for i := 2; i < n+2; i++ {
+		coset = fr.NewElement(uint64(i))
+		coExp.Exp(coset, big.NewInt(int64(n)))
+		if !coExp.Equal(&one) {
+			break
+		}
+	}
+	coExp.Sub(&coExp, &one)
+	coExp.Inverse(&coExp)
+
+	L := make([]fr.Element, n-1)
+	for i := 0; i < n-1; i++ {
+		L[i].Mul(&coset, &H[i])
+	}
+	return domain, H, L, coset, coExp
+}
+
+// Lag_i(L_l), the i-th lagrange polynomial of H evaluated at the l-th point of L
+func (crs *CRS) lagLH(l, i int) fr.Element {
+	n := len(crs.H)
+	return crs.lagLHDiag[((l-i)%n+n)%n]
 }
 
 func NewWTS(n int, weights []int, crs CRS) WTS {
+	w, err := NewWTSWithRand(n, weights, crs, rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// NewWTSWithRand is NewWTS with the signing keys, and any later randomness, sampled from rnd
+func NewWTSWithRand(n int, weights []int, crs CRS, rnd io.Reader) (WTS, error) {
+	return NewWTSContext(context.Background(), n, weights, crs, rnd, nil)
+}
+
+// NewWTSContext is NewWTSWithRand that reports the key generation progress to progress,
+// which may be nil, and stops with ctx.Err() when ctx is done
+func NewWTSContext(ctx context.Context, n int, weights []int, crs CRS, rnd io.Reader, progress ProgressFunc) (WTS, error) {
 	w := WTS{
 		n:       n,
 		weights: weights,
 		crs:     crs,
+		rnd:     rnd,
 	}
-	w.keyGen()
-	return w
+	if err := w.keyGen(ctx, progress); err != nil {
+		return WTS{}, err
+	}
+	return w, nil
+}
+
+// NewWTSFromSeed is NewWTS with the signing keys derived from seed with DeriveSignerKeys
+func NewWTSFromSeed(n int, weights []int, crs CRS, seed []byte) (WTS, error) {
+	sKeys, err := DeriveSignerKeys(seed, n)
+	if err != nil {
+		return WTS{}, err
+	}
+	w := WTS{
+		n:       n,
+		weights: weights,
+		crs:     crs,
+		rnd:     rand.Reader,
+	}
+	if err := w.keyGenFromKeys(context.Background(), sKeys, nil); err != nil {
+		return WTS{}, err
+	}
+	return w, nil
 }
 
 // Only to be used for benchmarking per signer key generation
-func (w *WTS) keyGenBench() {
-	var sKey fr.Element
-	var pKey, pKeyB bls.G1Jac
-	var aTau, hTau, hTauH bls.G1Affine
+func (w *WTS) keyGenBench() error {
+	sKey, err := RandomElement(w.rnd)
+	if err != nil {
+		return err
+	}
+	_, err = w.crs.SignerHints(0, sKey)
+	return err
+}
+
+// SignerHints is what the signer in one slot publishes for its signing key s
+type SignerHints struct {
+	PKey  bls.G1Affine   // [g^s]
+	PKeyB bls.G1Affine   // [g^{beta s}]
+	ATau  bls.G1Affine   // [g_alpha^s]
+	HTau  bls.G1Affine   // [g^{s.Lag_slot(tau)}]
+	HTauH bls.G1Affine   // [h^{s.Lag_slot(tau)}]
+	LTaus []bls.G1Affine // [g^{s.Lag_l(tau)}] for every point l of L
+}
 
-	sKey.SetRandom()
+// SignerHints computes the public key and the hints of the signer in slot with signing key sKey.
+// They are the entries of slot in the parameters that keyGen computes for the whole committee.
+func (crs *CRS) SignerHints(slot int, sKey fr.Element) (SignerHints, error) {
+	n := len(crs.H)
+	if slot < 0 || slot >= n {
+		return SignerHints{}, ErrSignerRange
+	}
 	skInt := sKey.BigInt(&big.Int{})
 
 	var wg sync.WaitGroup
-	wg.Add(w.n - 1)
-	lTaus := make([]bls.G1Affine, w.n)
-	for i := 0; i < w.n-1; i++ {
-		go func(i int) {
+	wg.Add(n - 1)
+	lTaus := make([]bls.G1Affine, n-1)
+	for l := 0; l < n-1; l++ {
+		go func(l int) {
 			defer wg.Done()
-			lTaus[i].ScalarMultiplication(&w.crs.lagLTaus[i], skInt)
-		}(i)
+			lTaus[l].ScalarMultiplication(&crs.lagLTaus[l], skInt)
+		}(l)
 	}
 
-	// TODO: work with Jacobian in all these cases
-	pKey.ScalarMultiplication(&w.crs.g1, skInt)
-	pKeyB.ScalarMultiplication(&w.crs.g1B, skInt)
-	aTau.ScalarMultiplication(&w.crs.gAlpha, skInt)
-	hTau.ScalarMultiplication(&w.crs.lagHTaus[0], skInt)
-	hTauH.ScalarMultiplication(&w.crs.lagHTausH[0], skInt)
+	var hints SignerHints
+	hints.PKey.ScalarMultiplication(&crs.g1a, skInt)
+	hints.PKeyB.ScalarMultiplication(&crs.g1Ba, skInt)
+	hints.ATau.ScalarMultiplication(&crs.gAlpha, skInt)
+	hints.HTau.ScalarMultiplication(&crs.lagHTaus[slot], skInt)
+	hints.HTauH.ScalarMultiplication(&crs.lagHTausH[slot], skInt)
 
 	wg.Wait()
+	hints.LTaus = lTaus
+	return hints, nil
+}
+
+// SignerHintsFromSeed is SignerHints for the key of slot that DeriveSignerKeys derives from seed,
+// so a signer of a committee built with NewWTSFromSeed can compute its own hints
+func (crs *CRS) SignerHintsFromSeed(seed []byte, slot int) (SignerHints, error) {
+	sKey, err := DeriveSignerKey(seed, slot)
+	if err != nil {
+		return SignerHints{}, err
+	}
+	return crs.SignerHints(slot, sKey)
 }
 
 // This is the keyGen function we use in the paper.
-func (w *WTS) keyGen() {
-	parties := make([]Party, w.n)
-	sKeys := make([]fr.Element, w.n)
+func (w *WTS) keyGen(ctx context.Context, progress ProgressFunc) error {
+	sKeys, err := RandomElements(w.rnd, w.n)
+	if err != nil {
+		return err
+	}
+	return w.keyGenFromKeys(ctx, sKeys, progress)
+}
 
-	for i := 0; i < w.n; i++ {
-		sKeys[i].SetRandom()
+// Computes the public parameters for the given signing keys
+func (w *WTS) keyGenFromKeys(ctx context.Context, sKeys []fr.Element, progress ProgressFunc) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
+	tr := newTracker(ctx, progress, PhaseKeyGen, w.n+4)
+	parties := make([]Party, w.n)
 
 	var wg sync.WaitGroup
 	wg.Add(3)
@@ -252,6 +391,7 @@ func (w *WTS) keyGen() {
 	go func() {
 		defer wg.Done()
 		pKeysB = bls.BatchScalarMultiplicationG1(&w.crs.g1Ba, sKeys)
+		tr.step()
 	}()
 
 	lTaus := make([][]bls.G1Affine, w.n)
@@ -259,10 +399,14 @@ func (w *WTS) keyGen() {
 		defer wg.Done()
 		for i := 0; i < w.n-1; i++ {
 			lTaus[i] = bls.BatchScalarMultiplicationG1(&w.crs.lagLTaus[i], sKeys)
+			if tr.step() != nil {
+				return
+			}
 		}
 	}()
 
 	pKeys := bls.BatchScalarMultiplicationG1(&w.crs.g1a, sKeys)
+	tr.step()
 	for i := 0; i < w.n; i++ {
 		parties[i] = Party{
 			weight:  w.weights[i],
@@ -275,6 +419,7 @@ func (w *WTS) keyGen() {
 	go func() {
 		defer wg.Done()
 		aTaus = bls.BatchScalarMultiplicationG1(&w.crs.gAlpha, sKeys)
+		tr.step()
 	}()
 
 	hTaus := make([]bls.G1Jac, w.n)
@@ -288,8 +433,12 @@ func (w *WTS) keyGen() {
 		hTausH[i].ScalarMultiplication(&lagHTauH, sKeys[i].BigInt(&big.Int{}))
 		pComm.AddAssign(&hTaus[i])
 	}
+	tr.step()
 
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	w.pp = Params{
 		pKeys:  pKeys,
@@ -301,59 +450,96 @@ func (w *WTS) keyGen() {
 		aTaus:  aTaus,
 	}
 	w.signers = parties
+	w.crsID = w.crs.ID()
+	w.weightComms()
+	w.updateCommitteeID()
+	tr.step()
+	return nil
+}
+
+// Computes the commitments wTau and wTaus to the weight vectors.
+// They are part of the CommitteeID, so they are set as soon as the weights are known.
+func (w *WTS) weightComms() {
+	weightsF := make([]fr.Element, w.n)
+	for i := 0; i < w.n; i++ {
+		weightsF[i] = fr.NewElement(uint64(w.weights[i]))
+	}
+	w.pp.wTau.MultiExp(w.crs.lagHTaus, weightsF, ecc.MultiExpConfig{})
+	w.pp.wTaus = make([]bls.G1Affine, len(w.dims))
+	for d, dim := range w.dims {
+		for i := 0; i < w.n; i++ {
+			weightsF[i] = fr.NewElement(uint64(dim[i]))
+		}
+		w.pp.wTaus[d].MultiExp(w.crs.lagHTaus, weightsF, ecc.MultiExpConfig{})
+	}
 }
 
 func (w *WTS) preProcess() {
-	lagLs := make([]bls.G1Jac, w.n-1)
-	var wg1 sync.WaitGroup
-	wg1.Add(w.n - 1)
-	for l := 0; l < w.n-1; l++ {
-		go func(l int) {
-			defer wg1.Done()
-			lagLs[l].MultiExp(w.pp.lTaus[l], w.crs.lagLH[l], ecc.MultiExpConfig{})
-		}(l)
+	if err := w.PreProcessContext(context.Background(), nil); err != nil {
+		panic(err)
 	}
-	wg1.Wait()
+}
 
-	var wg2 sync.WaitGroup
-	wg2.Add(1)
-	qTaus := make([]bls.G1Jac, w.n)
-	go func() {
-		defer wg2.Done()
-		exps := make([]fr.Element, w.n-1)
-		bases := make([]bls.G1Jac, w.n-1)
+// PreProcessContext computes the preprocessed parameters needed to combine signatures.
+// It reports its progress to progress, which may be nil, and stops with ctx.Err() when ctx is done.
+func (w *WTS) PreProcessContext(ctx context.Context, progress ProgressFunc) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	tr := newTracker(ctx, progress, PhasePreProcess, 2*w.n-1)
+	qTaus, err := w.preProcessHints(tr)
+	if err != nil {
+		return err
+	}
+	w.pp.qTaus = bls.BatchJacobianToAffineG1(qTaus)
+	return nil
+}
+
+// Computes the qTaus from the lTaus hints of the signers
+func (w *WTS) preProcessHints(tr *tracker) ([]bls.G1Jac, error) {
+	lagLs := make([]bls.G1Jac, w.n-1)
+	parallelFor(w.n-1, func(l int) {
+		if tr.ctx.Err() != nil {
+			return
+		}
+		lagLH := make([]fr.Element, w.n)
 		for i := 0; i < w.n; i++ {
-			var lTau bls.G1Jac
-			for l := 0; l < w.n-1; l++ {
-				lTau.FromAffine(&w.pp.lTaus[l][i])
-				bases[l] = lagLs[l]
-				bases[l].SubAssign(&lTau)
-				exps[l].Mul(&w.crs.lagLH[l][i], &w.crs.zHLInv) // Can also be pushed to Setup
-			}
-			qTaus[i].MultiExp(bls.BatchJacobianToAffineG1(bases), exps, ecc.MultiExpConfig{})
+			lagLH[i] = w.crs.lagLH(l, i)
 		}
-	}()
+		lagLs[l].MultiExp(w.pp.lTaus[l], lagLH, ecc.MultiExpConfig{})
+		tr.step()
+	})
+	if err := tr.ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	// pre-processing weights
-	weightsF := make([]fr.Element, w.n)
+	qTaus := make([]bls.G1Jac, w.n)
+	exps := make([]fr.Element, w.n-1)
+	bases := make([]bls.G1Jac, w.n-1)
 	for i := 0; i < w.n; i++ {
-		weightsF[i] = fr.NewElement(uint64(w.weights[i]))
+		var lTau bls.G1Jac
+		for l := 0; l < w.n-1; l++ {
+			lTau.FromAffine(&w.pp.lTaus[l][i])
+			bases[l] = lagLs[l]
+			bases[l].SubAssign(&lTau)
+			exps[l] = w.crs.lagLH(l, i)
+			exps[l].Mul(&exps[l], &w.crs.zHLInv) // Can also be pushed to Setup
+		}
+		qTaus[i].MultiExp(bls.BatchJacobianToAffineG1(bases), exps, ecc.MultiExpConfig{})
+		if err := tr.step(); err != nil {
+			return nil, err
+		}
 	}
-
-	wTau, _ := new(bls.G1Jac).MultiExp(w.crs.lagHTaus, weightsF, ecc.MultiExpConfig{})
-	w.pp.wTau = *new(bls.G1Affine).FromJacobian(wTau)
-
-	wg2.Wait()
-	w.pp.qTaus = bls.BatchJacobianToAffineG1(qTaus)
+	return qTaus, nil
 }
 
-func (w *WTS) weightsPf(signers []int) (bls.G1Jac, bls.G1Jac, bls.G1Jac) {
+func (w *WTS) weightsPf(signers []int, weights []int) (bls.G1Jac, bls.G1Jac, bls.G1Jac) {
 	bF := make([]fr.Element, w.n)
 	wF := make([]fr.Element, w.n)
 	rF := make([]fr.Element, w.n)
 
 	for i := 0; i < w.n; i++ {
-		wF[i] = fr.NewElement(uint64(w.weights[i]))
+		wF[i] = fr.NewElement(uint64(weights[i]))
 	}
 	for _, idx := range signers {
 		bF[idx] = fr.One()
@@ -426,7 +612,7 @@ func (w *WTS) binaryPf(signers []int) bls.G1Affine {
 
 // Takes the singing party and signs the message
 func (w *WTS) psign(msg Message, signer Party) bls.G2Jac {
-	roMsg, _ := bls.HashToG2(msg, []byte{})
+	roMsg, _ := hashMsg(w.CommitteeID(), msg)
 
 	return *new(bls.G2Jac).ScalarMultiplication(new(bls.G2Jac).FromAffine(&roMsg), signer.sKey.BigInt(&big.Int{}))
 }
@@ -466,104 +652,167 @@ func (w *WTS) secretPf(signers []int) bls.G1Jac {
 	return qrTau
 }
 
-// The combine function
-func (w *WTS) combine(signers []int, sigmas []bls.G2Jac) Sig {
-	var wg sync.WaitGroup
-	wg.Add(4)
-
-	var bTau, qTau, pTau, aggPk, aggPkB bls.G1Jac
-	var b2Tau, aggSig, bNegTau bls.G2Jac
-	weight := 0
-	go func() {
-		defer wg.Done()
-		for _, idx := range signers {
-			bTau.AddMixed(&w.crs.lagHTaus[idx])
-			b2Tau.AddMixed(&w.crs.lag2HTaus[idx])
-			qTau.AddMixed(&w.pp.qTaus[idx])
-			aggPk.AddMixed(&w.pp.pKeys[idx])
-			aggPkB.AddMixed(&w.pp.pKeysB[idx])
-			pTau.AddAssign(&w.pp.hTausH[idx])
-			weight += w.weights[idx]
+// Sorts the signers with their partial signatures and removes repeated entries.
+// The inputs are not modified.
+func (w *WTS) normalizeSigners(signers []int, sigmas []bls.G2Jac) ([]int, []bls.G2Jac, error) {
+	if len(signers) != len(sigmas) {
+		return nil, nil, fmt.Errorf("%w: %d signers, %d signatures", ErrSignerMismatch, len(signers), len(sigmas))
+	}
+	if len(signers) == 0 {
+		return nil, nil, ErrNoSigners
+	}
+	for _, idx := range signers {
+		if idx < 0 || idx >= w.n {
+			return nil, nil, fmt.Errorf("%w: %d not in [0, %d)", ErrSignerRange, idx, w.n)
 		}
-		bNegTau = w.crs.g2
-		bNegTau.SubAssign(&b2Tau)
+	}
 
-		// Aggregating the signature
-		for _, sig := range sigmas {
-			aggSig.AddAssign(&sig)
+	order := make([]int, len(signers))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return signers[order[a]] < signers[order[b]] })
+
+	nSigners := make([]int, 0, len(signers))
+	nSigmas := make([]bls.G2Jac, 0, len(signers))
+	for _, i := range order {
+		if k := len(nSigners) - 1; k >= 0 && nSigners[k] == signers[i] {
+			if !nSigmas[k].Equal(&sigmas[i]) {
+				return nil, nil, fmt.Errorf("%w: %d", ErrDuplicateSigner, signers[i])
+			}
+			continue
 		}
-	}()
+		nSigners = append(nSigners, signers[i])
+		nSigmas = append(nSigmas, sigmas[i])
+	}
+	return nSigners, nSigmas, nil
+}
 
-	var qB bls.G1Affine
-	go func() {
-		defer wg.Done()
-		qB = w.binaryPf(signers)
-	}()
+// The combine function.
+// The partial signatures are assumed to be valid, see pverify.
+func (w *WTS) combine(signers []int, sigmas []bls.G2Jac) (Sig, error) {
+	parts, err := w.combineParts(signers, sigmas)
+	if err != nil {
+		return Sig{}, err
+	}
+	xi := w.getFSChal(w.CommitteeID(), []bls.G1Affine{w.pp.pComm, w.pp.wTau, parts.bTau, parts.aggPk}, parts.weight)
+	return parts.sig(xi), nil
+}
 
-	var qwTau, rwTau, pwTauH bls.G1Jac
-	go func() {
-		defer wg.Done()
-		qwTau, rwTau, pwTauH = w.weightsPf(signers)
-	}()
+// Parts of a signature that are computed before the Fiat-Shamir challenge
+type combineParts struct {
+	bTau, aggPk              bls.G1Affine
+	qB                       bls.G1Affine
+	qTau, pTau, rTau, aggPkB bls.G1Jac
+	qwTau, rwTau, pwTauH     bls.G1Jac
+	bNegTau, aggSig          bls.G2Jac
+	weight                   int
+	signers                  []int // Normalized signer set
+}
+
+func (w *WTS) combineParts(signers []int, sigmas []bls.G2Jac) (combineParts, error) {
+	signers, sigmas, err := w.normalizeSigners(signers, sigmas)
+	if err != nil {
+		return combineParts{}, err
+	}
 
-	var rTau bls.G1Jac
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var qB bls.G1Affine
 	go func() {
 		defer wg.Done()
-		rTau = w.secretPf(signers)
+		qB = w.binaryPf(signers)
 	}()
+	agg := w.aggregate(signers, sigmas)
 	wg.Wait()
 
-	bTauAff := new(bls.G1Affine).FromJacobian(&bTau)
-	aggPkAff := new(bls.G1Affine).FromJacobian(&aggPk)
-	xi := w.getFSChal([]bls.G1Affine{w.pp.pComm, w.pp.wTau, *bTauAff, *aggPkAff}, weight)
+	return agg.parts(w.crs.g2, qB), nil
+}
+
+// Completes the signature for the challenge xi
+func (c combineParts) sig(xi fr.Element) Sig {
 	xiInt := xi.BigInt(&big.Int{})
 
-	qTau.AddAssign(qwTau.ScalarMultiplication(&qwTau, xiInt))
-	rTau.AddAssign(rwTau.ScalarMultiplication(&rwTau, xiInt))
-	pTau.AddAssign(pwTauH.ScalarMultiplication(&pwTauH, xiInt))
+	c.qTau.AddAssign(c.qwTau.ScalarMultiplication(&c.qwTau, xiInt))
+	c.rTau.AddAssign(c.rwTau.ScalarMultiplication(&c.rwTau, xiInt))
+	c.pTau.AddAssign(c.pwTauH.ScalarMultiplication(&c.pwTauH, xiInt))
 
 	pfO := IPAProof{
-		qTau: *new(bls.G1Affine).FromJacobian(&qTau),
-		rTau: *new(bls.G1Affine).FromJacobian(&rTau),
+		qTau: *new(bls.G1Affine).FromJacobian(&c.qTau),
+		rTau: *new(bls.G1Affine).FromJacobian(&c.rTau),
 	}
 
 	return Sig{
+		xi:      xi,
 		pi:      pfO,
-		qB:      qB,
-		ths:     weight,
-		bTau:    *bTauAff,
-		bNegTau: *new(bls.G2Affine).FromJacobian(&bNegTau),
-		pTau:    *new(bls.G1Affine).FromJacobian(&pTau),
-		aggSig:  aggSig,
-		aggPk:   *aggPkAff,
-		aggPkB:  *new(bls.G1Affine).FromJacobian(&aggPkB),
+		qB:      c.qB,
+		ths:     c.weight,
+		bTau:    c.bTau,
+		bNegTau: *new(bls.G2Affine).FromJacobian(&c.bNegTau),
+		pTau:    *new(bls.G1Affine).FromJacobian(&c.pTau),
+		aggSig:  c.aggSig,
+		aggPk:   c.aggPk,
+		aggPkB:  *new(bls.G1Affine).FromJacobian(&c.aggPkB),
 	}
 }
 
-// Get the Fiat-Shamir challenge for the IPA
-func (w *WTS) getFSChal(vals []bls.G1Affine, ths int) fr.Element {
+// Get the Fiat-Shamir challenge for the IPA, the transcript starts with the committee id
+// and ends with the claimed weight of each dimension
+func (w *WTS) getFSChal(cid CommitteeID, vals []bls.G1Affine, ths ...int) fr.Element {
 	n := len(vals)
-	hMsg := make([]byte, n*48+4)
+	hMsg := make([]byte, len(cid)+n*48+4*len(ths))
+	copy(hMsg, cid[:])
 	for i, val := range vals {
 		mBytes := val.Bytes()
-		copy(hMsg[i*48:(i+1)*48], mBytes[:])
+		copy(hMsg[len(cid)+i*48:len(cid)+(i+1)*48], mBytes[:])
+	}
+	for i, t := range ths {
+		binary.LittleEndian.PutUint32(hMsg[len(cid)+n*48+4*i:], uint32(t))
 	}
-	tBytes := make([]byte, 4)
-	binary.LittleEndian.PutUint32(tBytes, uint32(ths))
-	copy(hMsg[n*48:], tBytes)
 
 	hFunc := sha256.New()
-	hFunc.Reset()
-	return *new(fr.Element).SetBytes(hFunc.Sum(hMsg))
+	hFunc.Write(hMsg)
+	return *new(fr.Element).SetBytes(hFunc.Sum(nil))
 }
 
-// WTS global verify
+// WTS global verify, the boolean form of Verify
 func (w *WTS) gverify(msg Message, sigma Sig, ths int) bool {
+	return w.Verify(msg, sigma, ths) == nil
+}
+
+// Verify checks that sigma is a signature on msg of weight at least ths.
+// It stops at the first failed check and returns it as a *VerifyError.
+func (w *WTS) Verify(msg Message, sigma Sig, ths int) error {
+	fail := func(err error) error {
+		return &VerifyError{Err: err, Claimed: sigma.ths, Required: ths}
+	}
+
+	// 0. Checking the claimed weight
+	if sigma.ths < ths {
+		return fail(ErrInsufficientWeight)
+	}
+
+	cid := w.CommitteeID()
+	xi := w.getFSChal(cid, []bls.G1Affine{w.pp.pComm, w.pp.wTau, sigma.bTau, sigma.aggPk}, sigma.ths)
+	var wt bls.G1Affine
+	wt.ScalarMultiplication(&w.crs.g1a, big.NewInt(int64(sigma.ths)))
+
+	return w.verifyChecks(cid, msg, sigma, xi, []bls.G1Affine{wt}, fail)
+}
 
+// Runs the pairing checks of Verify, where wts are the weights of the signers in the exponent.
+// Dimension d is checked against its weight commitment with the challenge xi^(d+1).
+func (w *WTS) verifyChecks(cid CommitteeID, msg Message, sigma Sig, xi fr.Element, wts []bls.G1Affine, fail func(error) error) error {
 	// 1. Checking aggregated signature is correct
-	roMsg, _ := bls.HashToG2(msg, []byte{})
-	res, _ := bls.PairingCheck([]bls.G1Affine{sigma.aggPk, w.crs.g1InvAff}, []bls.G2Affine{roMsg, *new(bls.G2Affine).FromJacobian(&sigma.aggSig)})
+	roMsg, _ := hashMsg(cid, msg)
+	if res, _ := bls.PairingCheck([]bls.G1Affine{sigma.aggPk, w.crs.g1InvAff}, []bls.G2Affine{roMsg, *new(bls.G2Affine).FromJacobian(&sigma.aggSig)}); !res {
+		return fail(ErrAggSig)
+	}
+	return w.verifyProof(sigma, xi, wts, fail)
+}
 
+// Runs the checks of Verify on the proof of sigma, every check but the BLS one
+func (w *WTS) verifyProof(sigma Sig, xi fr.Element, wts []bls.G1Affine, fail func(error) error) error {
 	pi := sigma.pi
 
 	// Computing g^{1/n}
@@ -574,37 +823,52 @@ func (w *WTS) gverify(msg Message, sigma Sig, ths int) bool {
 	hNInv := *new(bls.G2Affine).ScalarMultiplication(&w.crs.h2a, nInv.BigInt(&big.Int{}))
 
 	// 2. Checking degree of the aggregated public key
-	valid, _ := bls.PairingCheck([]bls.G1Affine{sigma.aggPk, sigma.aggPkB}, []bls.G2Affine{w.crs.g2Ba, w.crs.g2InvAff})
-	res = res && valid
+	if valid, _ := bls.PairingCheck([]bls.G1Affine{sigma.aggPk, sigma.aggPkB}, []bls.G2Affine{w.crs.g2Ba, w.crs.g2InvAff}); !valid {
+		return fail(ErrAggPkDegree)
+	}
 
-	// 3. Checking the binary relation
+	// 3. Checking that bTau and bNegTau commit to the same bitvector and that it is binary.
+	// Without the first check bTau = qB = 0 passes the binary relation for any bNegTau.
+	if valid, _ := bls.PairingCheck([]bls.G1Affine{sigma.bTau, w.crs.g1a, w.crs.g1InvAff}, []bls.G2Affine{w.crs.g2a, sigma.bNegTau, w.crs.g2a}); !valid {
+		return fail(ErrBitvector)
+	}
 	// TODO: Replace Pair with PairingCheck
 	lhs, _ := bls.Pair([]bls.G1Affine{sigma.bTau}, []bls.G2Affine{sigma.bNegTau})
 	rhs, _ := bls.Pair([]bls.G1Affine{sigma.qB}, []bls.G2Affine{w.crs.vHTau})
-	res = res && lhs.Equal(&rhs)
+	if !lhs.Equal(&rhs) {
+		return fail(ErrBinary)
+	}
 
 	var b2Tau bls.G2Affine
 	b2Tau.Sub(&w.crs.g2a, &sigma.bNegTau)
 
-	xi := w.getFSChal([]bls.G1Affine{w.pp.pComm, w.pp.wTau, sigma.bTau, sigma.aggPk}, sigma.ths)
-
-	oTau := new(bls.G1Affine).ScalarMultiplication(&w.pp.wTau, xi.BigInt(&big.Int{}))
-	oTau.Add(oTau, &w.pp.pComm)
-
-	tF := fr.NewElement(uint64(sigma.ths))
-	xiT := *new(fr.Element).Mul(&xi, &tF)
-	mu := new(bls.G1Affine).ScalarMultiplication(&w.crs.g1a, xiT.BigInt(&big.Int{}))
-	mu.Add(mu, &sigma.aggPk)
+	oTau, mu := w.pp.pComm, sigma.aggPk
+	xiD := xi
+	for d, wt := range wts {
+		wTau := w.pp.wTau
+		if d > 0 {
+			wTau = w.pp.wTaus[d-1]
+		}
+		var t bls.G1Affine
+		xiInt := xiD.BigInt(&big.Int{})
+		oTau.Add(&oTau, t.ScalarMultiplication(&wTau, xiInt))
+		mu.Add(&mu, t.ScalarMultiplication(&wt, xiInt))
+		xiD.Mul(&xiD, &xi)
+	}
 
 	// 4. Checking that the inner-product is correct
-	lhs, _ = bls.Pair([]bls.G1Affine{*oTau}, []bls.G2Affine{b2Tau})
-	rhs, _ = bls.Pair([]bls.G1Affine{pi.qTau, pi.rTau, *mu}, []bls.G2Affine{w.crs.vHTau, w.crs.g2Tau, gNInv})
-	res = res && lhs.Equal(&rhs)
+	lhs, _ = bls.Pair([]bls.G1Affine{oTau}, []bls.G2Affine{b2Tau})
+	rhs, _ = bls.Pair([]bls.G1Affine{pi.qTau, pi.rTau, mu}, []bls.G2Affine{w.crs.vHTau, w.crs.g2Tau, gNInv})
+	if !lhs.Equal(&rhs) {
+		return fail(ErrInnerProduct)
+	}
 
 	// 5. Checking rTau is of correct degree
 	lhs, _ = bls.Pair([]bls.G1Affine{sigma.pTau}, []bls.G2Affine{w.crs.g2a})
-	rhs, _ = bls.Pair([]bls.G1Affine{pi.rTau, *mu}, []bls.G2Affine{w.crs.hTauHAff, hNInv})
-	res = res && lhs.Equal(&rhs)
+	rhs, _ = bls.Pair([]bls.G1Affine{pi.rTau, mu}, []bls.G2Affine{w.crs.hTauHAff, hNInv})
+	if !lhs.Equal(&rhs) {
+		return fail(ErrRTauDegree)
+	}
 
-	return res && (ths <= sigma.ths)
+	return nil
 }
@@ -14,6 +14,32 @@ import (
 )
 
 var NUM_NODES = flag.Int("signers", 1<<8, "Number of Signers")
+var SNAPSHOT = flag.String("snapshot", "", "Stake snapshot (.csv or .json) to take the signers and weights from")
+
+// Weights for the benchmarks, weights[i] = i unless a snapshot is given
+func benchWeights(b *testing.B) (int, []int) {
+	if *SNAPSHOT == "" {
+		n := *NUM_NODES
+		weights := make([]int, n)
+		for i := 0; i < n; i++ {
+			weights[i] = i
+		}
+		return n, weights
+	}
+	snap, err := LoadSnapshot(*SNAPSHOT)
+	if err != nil {
+		b.Fatal(err)
+	}
+	weights, err := snap.Weights()
+	if err != nil {
+		q, err := QuantizeWithBound(snap.Stakes(), big.NewRat(1, 100))
+		if err != nil {
+			b.Fatal(err)
+		}
+		weights = q.Weights
+	}
+	return snap.Slots(), weights
+}
 
 func BenchmarkCompF(b *testing.B) {
 	logN := 15
@@ -98,6 +124,15 @@ func TestKeyGen(t *testing.T) {
 		assert.Equal(t, hTau.Equal(&w.pp.hTaus[i]), true)
 	}
 
+	// Checking g^alpha with alpha = sum_i Lag_i(tau)/omega^i
+	var alpha, div fr.Element
+	for i := 0; i < n; i++ {
+		alpha.Add(&alpha, div.Div(&lagH[i], &w.crs.H[i]))
+	}
+	var gAlpha bls.G1Affine
+	gAlpha.ScalarMultiplication(&w.crs.g1a, alpha.BigInt(&big.Int{}))
+	assert.Equal(t, gAlpha.Equal(&w.crs.gAlpha), true)
+
 	// Checking aggregated public key correctness
 	var pComm bls.G1Affine
 	pComm.ScalarMultiplication(&w.crs.g1a, skTau.BigInt(&big.Int{}))
@@ -130,6 +165,7 @@ func BenchmarkKeyGen(b *testing.B) {
 		n:       n,
 		weights: weights,
 		crs:     crs,
+		rnd:     NewSeededReader([]byte("keygen")),
 	}
 
 	b.ResetTimer()
@@ -138,6 +174,16 @@ func BenchmarkKeyGen(b *testing.B) {
 	}
 }
 
+func BenchmarkSetup(b *testing.B) {
+	flag.Parse()
+	n := *NUM_NODES
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		GenCRS(n)
+	}
+}
+
 func BenchmarkCComp1(b *testing.B) {
 	n := 1 << 15
 	scalars := make([]fr.Element, n)
@@ -244,7 +290,7 @@ func TestBin(t *testing.T) {
 	assert.Equal(t, lhs.Equal(&rhs), true, "Proving Binary relation!")
 
 	// Checking weights relation
-	qwTau, rwTau, _ := w.weightsPf(signers)
+	qwTau, rwTau, _ := w.weightsPf(signers, w.weights)
 	qwTauAff := *new(bls.G1Affine).FromJacobian(&qwTau)
 	rwTauAff := *new(bls.G1Affine).FromJacobian(&rwTau)
 
@@ -261,8 +307,6 @@ func TestBin(t *testing.T) {
 
 func TestWTS(t *testing.T) {
 	msg := []byte("hello world")
-	roMsg, _ := bls.HashToG2(msg, []byte{})
-
 	n := 1 << 7
 	weights := make([]int, n)
 	for i := 0; i < n; i++ {
@@ -272,6 +316,7 @@ func TestWTS(t *testing.T) {
 	crs := GenCRS(n)
 	w := NewWTS(n, weights, crs)
 	w.preProcess()
+	roMsg, _ := hashMsg(w.CommitteeID(), msg)
 
 	var signers []int
 	var sigmas []bls.G2Jac
@@ -286,19 +331,16 @@ func TestWTS(t *testing.T) {
 		assert.Equal(t, w.pverify(roMsg, sigmas[i], w.signers[idx].pKeyAff), true)
 	}
 
-	sig := w.combine(signers, sigmas)
+	sig, err := w.combine(signers, sigmas)
+	assert.NoError(t, err)
 	assert.Equal(t, w.gverify(msg, sig, ths), true)
 }
 
 func BenchmarkWTS(b *testing.B) {
 	flag.Parse()
-	n := *NUM_NODES
+	n, weights := benchWeights(b)
 
 	msg := []byte("hello world")
-	weights := make([]int, n)
-	for i := 0; i < n; i++ {
-		weights[i] = i
-	}
 
 	crs := GenCRS(n)
 	w := NewWTS(n, weights, crs)
@@ -330,7 +372,7 @@ func BenchmarkWTS(b *testing.B) {
 	b.Run("Agg-N:"+strconv.Itoa(n), func(b *testing.B) {
 		b.ResetTimer()
 		for i := 0; i < b.N; i++ {
-			sig = w.combine(signers, sigmas)
+			sig, _ = w.combine(signers, sigmas)
 		}
 	})
 